@@ -3,9 +3,7 @@ package regattaClock
 import (
 	"fmt"
 	"image/color"
-	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -14,6 +12,7 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
 )
 
 // App represents the main application
@@ -24,33 +23,53 @@ type App struct {
 	regattaTitle       *canvas.Text
 	regattaDate        *canvas.Text
 	scheduledRaces     *canvas.Text
-	tableRows          []LapTableRow
+	captureList        *widget.List
+	lapRows            map[fyne.CanvasObject]*LapTableRow
+	snapshot           timing.Results
+	splitLists         []*widget.List
+	splitRows          map[fyne.CanvasObject]*splitRow
+	history            []timing.HistoryEntry // Undo history shown in the history window
+	historyWindow      fyne.Window           // Nil unless the history window is open
+	historyList        *widget.List
+	historyButtons     []*widget.Button // Undo and Redo in the history window
 	resultsTable       [][]string
-	lapTimes           []lapTime
+	session            *timing.RaceSession
+	raceLog            *timing.EventLog             // Race window's session events
+	journal            *timing.Journal              // Nil if the session is not journaled
+	journalFailed      bool                         // Whether a journal write error has been shown
+	raceLogs           map[RaceKey]*timing.EventLog // Latest session events of each race timed
+	raceWindows        map[RaceKey]*App             // Open race windows by race
+	headRaceLogs       map[string]*timing.EventLog  // Latest session events of each head race timed, by session
+	headRaceWindows    map[string]*headRaceWindow   // Open head race windows by session
+	timedRace          RaceKey                      // Race timed in a race window
+	amended            bool                         // Whether the race's approved results have been edited
+	raceList           *fyne.Container              // Race tree rows in the main window
+	raceTreeChanged    func()                       // Redraws the main window's race tree, for race windows
+	projectPath        string                       // Project file the regatta was opened from or saved to
+	timeSource         timing.Clock
+	refreshing         bool
 	raceNumber         *widget.Entry
 	winningTime        *widget.Entry
 	regattaData        *RegattaData
-	clockState         *clockState
+	layouts            []*WorkbookLayout
+	laneCount          int
+	stopChan           chan struct{}
 	resultsTableWidget *widget.Table
 }
 
-type clockState struct {
-	isRunning bool
-	isCleared bool
-	startTime time.Time
-	stopChan  chan struct{}
-}
-
 func NewApp(app fyne.App) *App {
 	regattaApp := &App{
-		window:   app.NewWindow("Regatta Clock"),
-		app:      app,
-		lapTimes: make([]lapTime, 0),
-		clockState: &clockState{
-			isRunning: false,
-			isCleared: true,
-			stopChan:  make(chan struct{}),
-		},
+		window:          app.NewWindow("Regatta Clock"),
+		app:             app,
+		session:         timing.NewRaceSession(defaultLaneCount),
+		timeSource:      timing.RealClock{},
+		layouts:         []*WorkbookLayout{DefaultLayout()},
+		laneCount:       defaultLaneCount,
+		stopChan:        make(chan struct{}),
+		raceLogs:        make(map[RaceKey]*timing.EventLog),
+		raceWindows:     make(map[RaceKey]*App),
+		headRaceLogs:    make(map[string]*timing.EventLog),
+		headRaceWindows: make(map[string]*headRaceWindow),
 	}
 
 	regattaApp.initAppData()
@@ -74,18 +93,19 @@ func (a *App) Run() {
 }
 
 func (a *App) startClockUpdate() {
-	ticker := time.NewTicker(100 * time.Millisecond) // Update every 0.1 seconds
+	// Update every unit of precision, but no faster than every 0.01 seconds
+	interval := time.Duration(a.session.Rules().Precision.Unit())
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			if a.clockState.isRunning {
-				elapsed := time.Since(a.clockState.startTime)
-				minutes := int(elapsed.Minutes()) % 60
-				seconds := int(elapsed.Seconds()) % 60
-				tenths := int(elapsed.Milliseconds()/100) % 10
-				formatted := fmt.Sprintf("%02d:%02d.%d", minutes, seconds, tenths)
+			if a.session.State() == timing.StateRunning {
+				formatted := a.session.Rules().Precision.Format(a.session.Elapsed())
 
 				// Use fyne.Do to update UI on the main thread
 				fyne.Do(func() {
@@ -95,7 +115,7 @@ func (a *App) startClockUpdate() {
 					}
 				})
 			}
-		case <-a.clockState.stopChan:
+		case <-a.stopChan:
 			return
 		}
 	}
@@ -109,28 +129,49 @@ func (a *App) initAppData() {
 	a.setupWinningTime()
 
 	if a.resultsTable == nil {
-		a.resultsTable = [][]string{
-			{"", "Lane 1", "Lane 2", "Lane 3", "Lane 4", "Lane 5", "Lane 6"},
-			{"", "", "", "", "", "", ""},
-			{"Place", "", "", "", "", "", ""},
-			{"Split", "", "", "", "", "", ""},
-			{"Time", "", "", "", "", "", ""},
-			{"", "", "", "", "", "", ""}, // Add fifth data row
-			{"", "", "", "", "", "", ""}, // Add sixth row for storing original place values
-		}
+		a.resultsTable = newResultsTable(a.laneCount, a.session.SplitStations())
 	}
+}
 
-	// Ensure we have enough rows for the data
-	if len(a.resultsTable) < 7 {
-		// Add any missing rows
-		for i := len(a.resultsTable); i < 7; i++ {
-			a.resultsTable = append(a.resultsTable, make([]string, 7))
-		}
+// resultsStationRow is the results table row of the first split station
+const resultsStationRow = 6
+
+// newResultsTable creates an empty results table with a column per lane and
+// rows for the lane header, school, additional info, Place, Split and Time,
+// then a row for each split station
+func newResultsTable(laneCount int, splitStations []int) [][]string {
+	resultsTable := make([][]string, resultsStationRow+len(splitStations))
+	for i := range resultsTable {
+		resultsTable[i] = make([]string, laneCount+1)
 	}
+
+	// Set up the results table headers
+	resultsTable[3][0] = "Place"
+	resultsTable[4][0] = "Split"
+	resultsTable[5][0] = "Time"
+	for station, distance := range splitStations {
+		resultsTable[resultsStationRow+station][0] = fmt.Sprintf("%dm", distance)
+	}
+
+	// Always show all lane headers
+	for lane := 1; lane <= laneCount; lane++ {
+		resultsTable[0][lane] = fmt.Sprintf("Lane %d", lane)
+	}
+	return resultsTable
+}
+
+// zeroTime is a zero time at the session's precision, such as "00:00.0"
+func (a *App) zeroTime() string {
+	return a.session.Rules().Precision.Format(0)
+}
+
+// validLane reports whether laneNum is a lane on this race's course
+func (a *App) validLane(laneNum int) bool {
+	return laneNum >= 1 && laneNum <= a.laneCount
 }
 
 func (a *App) setClock() {
-	a.clock = canvas.NewText(zeroTime, color.White)
+	a.clock = canvas.NewText(a.zeroTime(), color.White)
 	a.clock.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
 	a.clock.Alignment = fyne.TextAlignCenter
 	a.clock.TextSize = 48
@@ -162,6 +203,7 @@ func (a *App) setupContent() *fyne.Container {
 		container.NewCenter(a.clock),
 		a.buttonPanel(),
 		a.lapTable(),
+		a.splitPanel(),
 		widget.NewForm(a.winningTimeInput()),
 	)
 
@@ -199,415 +241,158 @@ func (a *App) setupStartupDialog() {
 	)
 }
 
+// refreshContent takes a new snapshot of the session, redraws the capture
+// list and updates the results table
 func (a *App) refreshContent() {
-	// First pass: calculate adjusted place numbers
-	adjustedPlaces := make([]int, len(a.lapTimes))
-	for i := 0; i < len(a.lapTimes); i++ {
-		adjustedPlaces[i] = a.lapTimes[i].number
-	}
-
-	// Calculate time adjustments if winning time is set
-	var timeAdjustment time.Duration
-	if a.winningTime.Text != emptyString {
-		winningTime, err := parseTime(a.winningTime.Text)
-		if err == nil && len(a.lapTimes) > 0 {
-			// Calculate the adjustment based on the first lap time
-			firstLapTime, err := parseTime(a.lapTimes[0].time)
-			if err == nil {
-				timeAdjustment = winningTime - firstLapTime
-				// Store the calculated time in the lapTime struct
-				a.lapTimes[0].calculatedTime = formatTime(winningTime)
-			}
-		}
+	a.snapshot = a.session.Results()
+	if a.captureList != nil {
+		a.captureList.Refresh()
 	}
-
-	// First pass: update all time labels and calculated times
-	for i := 0; i < len(a.lapTimes); i++ {
-		if timeAdjustment != 0 {
-			lapTime, err := parseTime(a.lapTimes[i].time)
-			if err == nil {
-				adjustedTime := lapTime + timeAdjustment
-				adjustedTimeStr := formatTime(adjustedTime)
-				a.lapTimes[i].calculatedTime = adjustedTimeStr
-				// Update results table if OOF is set
-				if oof := a.lapTimes[i].oof; oof != emptyString {
-					if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-						a.resultsTable[5][laneNum] = adjustedTimeStr
-					}
-				}
+	a.refreshSplits()
+	a.refreshHistory()
+
+	// Split shows the lane's split station times, each of which also has a
+	// row of its own with the segment since the station before
+	precision := a.snapshot.Rules.Precision
+	for lane := 1; lane <= a.laneCount; lane++ {
+		laneResult := a.snapshot.Lane(lane)
+		splits := laneSplitTimes(laneResult, precision)
+		a.resultsTable[3][lane] = laneResult.Place
+		a.resultsTable[4][lane] = splitSummary(splits)
+		a.resultsTable[5][lane] = emptyString
+		if laneResult.Timed {
+			a.resultsTable[5][lane] = precision.Format(laneResult.Time)
+		}
+		for station, split := range laneResult.Splits {
+			row := resultsStationRow + station
+			if row >= len(a.resultsTable) {
+				break
 			}
-		} else {
-			a.lapTimes[i].calculatedTime = a.lapTimes[i].time
-			// Update results table if OOF is set
-			if oof := a.lapTimes[i].oof; oof != emptyString {
-				if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-					a.resultsTable[5][laneNum] = a.lapTimes[i].time
-				}
+			a.resultsTable[row][lane] = emptyString
+			if timed, ok := splitAt(splits, split.Distance); ok {
+				a.resultsTable[row][lane] = timed.Text()
 			}
 		}
 	}
 
-	// Second pass: update all rows and resultsTable
-	for i := 0; i < 6; i++ {
-		if i < len(a.lapTimes) {
-			// Set OOF entry
-			a.tableRows[i].oofEntry.SetText(a.lapTimes[i].oof)
-			if !a.clockState.isRunning {
-				a.tableRows[i].oofEntry.Enable()
-				// Set up the OnChanged handler for OOF editing
-				row := i // Capture the row index
-				a.tableRows[i].oofEntry.OnChanged = func(text string) {
-					if !a.clockState.isRunning && row < len(a.lapTimes) {
-						// Update resultsTable if OOF matches a lane number
-						if laneNum, err := strconv.Atoi(text); err == nil && laneNum >= 1 && laneNum <= 6 {
-							// Check for duplicate OOF values in other rows
-							isDuplicate := false
-							for j := 0; j < len(a.lapTimes); j++ {
-								if j != row && a.lapTimes[j].oof == text {
-									isDuplicate = true
-									break
-								}
-							}
-
-							if !isDuplicate {
-								// Store previous OOF value before updating
-								prevOOF := a.lapTimes[row].oof
-								// Update the lap time's OOF value
-								a.lapTimes[row].oof = text
-								// Update Place, Split, and Time rows in resultsTable
-								a.resultsTable[3][laneNum] = a.tableRows[row].placeButton.Text // Update Place
-								a.resultsTable[4][laneNum] = a.tableRows[row].splitEntry.Text  // Update Split
-								a.resultsTable[5][laneNum] = a.lapTimes[row].calculatedTime    // Update Time with calculated time
-								// Clear previous lane if it was different
-								if prevOOF != emptyString && prevOOF != text {
-									if prevLaneNum, err := strconv.Atoi(prevOOF); err == nil && prevLaneNum >= 1 && prevLaneNum <= 6 {
-										a.resultsTable[3][prevLaneNum] = emptyString // Clear Place
-										a.resultsTable[4][prevLaneNum] = emptyString // Clear Split
-										a.resultsTable[5][prevLaneNum] = emptyString // Clear Time
-									}
-								}
-								a.window.Content().Refresh()
-							} else {
-								// If duplicate, clear the input
-								a.tableRows[row].oofEntry.SetText(emptyString)
-								// Clear the previous lane if it exists
-								if prevOOF := a.lapTimes[row].oof; prevOOF != emptyString {
-									if prevLaneNum, err := strconv.Atoi(prevOOF); err == nil && prevLaneNum >= 1 && prevLaneNum <= 6 {
-										a.resultsTable[3][prevLaneNum] = emptyString // Clear Place
-										a.resultsTable[4][prevLaneNum] = emptyString // Clear Split
-										a.resultsTable[5][prevLaneNum] = emptyString // Clear Time
-										a.window.Content().Refresh()
-									}
-								}
-								a.lapTimes[row].oof = emptyString
-							}
-						} else {
-							// If OOF is cleared or invalid, clear the previous lane
-							prevOOF := a.lapTimes[row].oof
-							// Update the lap time's OOF value
-							a.lapTimes[row].oof = text
-							if prevOOF != emptyString {
-								if prevLaneNum, err := strconv.Atoi(prevOOF); err == nil && prevLaneNum >= 1 && prevLaneNum <= 6 {
-									a.resultsTable[3][prevLaneNum] = emptyString // Clear Place
-									a.resultsTable[4][prevLaneNum] = emptyString // Clear Split
-									a.resultsTable[5][prevLaneNum] = emptyString // Clear Time
-									a.window.Content().Refresh()
-								}
-							}
-						}
-					}
-				}
-
-				// Set up the OnSubmitted handler for OOF editing (Tab or Enter)
-				a.tableRows[i].oofEntry.OnSubmitted = func(text string) {
-					if !a.clockState.isRunning && row < len(a.tableRows) && row < len(a.lapTimes) {
-						// Move focus to next row's OOF entry if it exists
-						if row+1 < len(a.tableRows) && row+1 < len(a.lapTimes) {
-							// Clear any existing text in the next entry
-							a.tableRows[row+1].oofEntry.SetText(emptyString)
-							// Move focus to the next entry
-							a.window.Canvas().Focus(a.tableRows[row+1].oofEntry)
-						}
-					}
-				}
-			} else {
-				a.tableRows[i].oofEntry.Disable()
-			}
-
-			// Set Place button
-			placeText := fmt.Sprintf("%d", adjustedPlaces[i])
-			a.tableRows[i].placeButton.SetText(placeText)
-			a.tableRows[i].splitEntry.SetText(a.lapTimes[i].time)
-			a.tableRows[i].timeLabel.SetText(a.lapTimes[i].calculatedTime)
-
-			// Set up the place button click handler
-			row := i // Capture the row index
-			a.tableRows[i].placeButton.OnTapped = func() {
-				if !a.clockState.isRunning {
-					// Get the lane number from OOF
-					oof := a.lapTimes[row].oof
-					if oof == emptyString {
-						return // Don't allow editing if no lane is assigned
-					}
-
-					laneNum, err := strconv.Atoi(oof)
-					if err != nil || laneNum < 1 || laneNum > 6 {
-						return // Invalid lane number
-					}
-
-					// Create a dialog to edit the place value
-					currentPlace := a.resultsTable[3][laneNum]
-					options := []string{"DNS", "DNF", "DQ", "Next Place"}
-
-					selectWidget := widget.NewSelect(options, func(value string) {
-						// Store the old place value
-						oldPlace := a.resultsTable[3][laneNum]
-
-						// Handle DQ/DNF/DNS status
-						if value == "DQ" || value == "DNF" || value == "DNS" {
-							// Update the place value in the results table
-							a.resultsTable[3][laneNum] = value
-
-							// Clear Split and Time values in results table
-							a.resultsTable[4][laneNum] = emptyString
-							a.resultsTable[5][laneNum] = emptyString
-
-							// If the new place is DQ, adjust other place values
-							if value == "DQ" {
-								// Convert old place to number if possible
-								if oldPlaceNum, err := strconv.Atoi(oldPlace); err == nil {
-									// Decrease place values greater than the DQ'd place
-									for l := 1; l <= 6; l++ {
-										if l != laneNum {
-											if placeStr := a.resultsTable[3][l]; placeStr != emptyString {
-												if placeNum, err := strconv.Atoi(placeStr); err == nil && placeNum > oldPlaceNum {
-													a.resultsTable[3][l] = fmt.Sprintf("%d", placeNum-1)
-													// Update the corresponding place button
-													for i := 0; i < len(a.lapTimes); i++ {
-														if a.lapTimes[i].oof == fmt.Sprintf("%d", l) {
-															a.tableRows[i].placeButton.SetText(a.resultsTable[3][l])
-															break
-														}
-													}
-												}
-											}
-										}
-									}
-								}
-							} else if value == "Next Place" {
-								// First, update the current lane to Next Place
-								a.resultsTable[3][laneNum] = "Next Place"
-
-								// Restore Split and Time values from the lap table
-								for i := 0; i < len(a.lapTimes); i++ {
-									if a.lapTimes[i].oof == fmt.Sprintf("%d", laneNum) {
-										a.resultsTable[4][laneNum] = a.tableRows[i].splitEntry.Text
-										a.resultsTable[5][laneNum] = a.lapTimes[i].calculatedTime
-										break
-									}
-								}
-
-								// Now rescan and reassign all place numbers based on lap times sequence
-								nextPlace := 1
-								for i := 0; i < len(a.lapTimes); i++ {
-									if oof := a.lapTimes[i].oof; oof != emptyString {
-										if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-											placeStr := a.resultsTable[3][laneNum]
-											if placeStr != "DQ" && placeStr != "DNS" && placeStr != "DNF" && placeStr != emptyString {
-												a.resultsTable[3][laneNum] = fmt.Sprintf("%d", nextPlace)
-												// Update the corresponding place button
-												a.tableRows[i].placeButton.SetText(a.resultsTable[3][laneNum])
-												nextPlace++
-											}
-										}
-									}
-								}
-							}
-						} else if value == "Next Place" {
-							// First, update the current lane to Next Place
-							a.resultsTable[3][laneNum] = "Next Place"
-
-							// Restore Split and Time values from the lap table
-							for i := 0; i < len(a.lapTimes); i++ {
-								if a.lapTimes[i].oof == fmt.Sprintf("%d", laneNum) {
-									a.resultsTable[4][laneNum] = a.tableRows[i].splitEntry.Text
-									a.resultsTable[5][laneNum] = a.lapTimes[i].calculatedTime
-									break
-								}
-							}
-
-							// Now rescan and reassign all place numbers based on lap times sequence
-							nextPlace := 1
-							for i := 0; i < len(a.lapTimes); i++ {
-								if oof := a.lapTimes[i].oof; oof != emptyString {
-									if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-										placeStr := a.resultsTable[3][laneNum]
-										if placeStr != "DQ" && placeStr != "DNS" && placeStr != "DNF" && placeStr != emptyString {
-											a.resultsTable[3][laneNum] = fmt.Sprintf("%d", nextPlace)
-											// Update the corresponding place button
-											a.tableRows[i].placeButton.SetText(a.resultsTable[3][laneNum])
-											nextPlace++
-										}
-									}
-								}
-							}
-						}
-
-						// Update the place button text
-						a.tableRows[row].placeButton.SetText(a.resultsTable[3][laneNum])
-
-						// Refresh the window content to show the updated place values
-						a.window.Content().Refresh()
-					})
-
-					// Set the current value if it exists in options
-					for _, option := range options {
-						if option == currentPlace {
-							selectWidget.SetSelected(option)
-							break
-						}
-					}
-
-					dialog.ShowCustom(
-						"Edit Place",
-						"Close",
-						selectWidget,
-						a.window,
-					)
-				}
-			}
-
-			// Update resultsTable if OOF is set
-			if oof := a.lapTimes[i].oof; oof != emptyString {
-				if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-					a.resultsTable[3][laneNum] = placeText                    // Update Place
-					a.resultsTable[4][laneNum] = a.lapTimes[i].time           // Update Split
-					a.resultsTable[5][laneNum] = a.lapTimes[i].calculatedTime // Update Time
-				}
-			}
-
-			// Set up the OnChanged handler for split time editing
-			if !a.clockState.isRunning {
-				row := i // Capture the row index
-				a.tableRows[i].splitEntry.OnChanged = func(text string) {
-					if !a.clockState.isRunning && row < len(a.lapTimes) {
-						// Update the lap time
-						a.lapTimes[row].time = text
-
-						// Calculate and update the time label
-						lapTime, err := parseTime(text)
-						if err == nil {
-							if timeAdjustment != 0 {
-								adjustedTime := lapTime + timeAdjustment
-								adjustedTimeStr := formatTime(adjustedTime)
-								a.tableRows[row].timeLabel.SetText(adjustedTimeStr)
-								a.lapTimes[row].calculatedTime = adjustedTimeStr
-							} else {
-								a.tableRows[row].timeLabel.SetText(formatTime(lapTime))
-								a.lapTimes[row].calculatedTime = formatTime(lapTime)
-							}
-						}
-
-						// Update resultsTable if OOF matches a lane number
-						if oof := a.lapTimes[row].oof; oof != emptyString {
-							if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-								// Update Place, Split, and Time rows in resultsTable
-								a.resultsTable[3][laneNum] = a.tableRows[row].placeButton.Text // Update Place
-								a.resultsTable[4][laneNum] = text                              // Update Split
-								a.resultsTable[5][laneNum] = a.lapTimes[row].calculatedTime    // Update Time
-								a.window.Content().Refresh()
-							}
-						}
-					}
-				}
-			}
-		} else {
-			// Clear row
-			a.tableRows[i].oofEntry.SetText(emptyString)
-			a.tableRows[i].oofEntry.Disable()
-			a.tableRows[i].placeButton.SetText(emptyString)
-			a.tableRows[i].splitEntry.SetText(emptyString)
-			a.tableRows[i].timeLabel.SetText(emptyString)
-		}
+	if content := a.window.Content(); content != nil {
+		content.Refresh()
 	}
+	a.checkAmendment()
+	a.checkJournal()
 }
 
-// parseTime parses a time string in format "00:00.0" or "00:00:00.000" to time.Duration
-func parseTime(timeStr string) (time.Duration, error) {
-	if timeStr == emptyString {
-		return 0, nil
+// assignLane assigns a capture to the lane typed in its OOF entry, clearing
+// the entry if that lane is already taken
+func (a *App) assignLane(index int, text string) {
+	if a.refreshing || a.session.State() == timing.StateRunning {
+		return
 	}
 
-	// Try parsing as "00:00.0" format first
-	parts := strings.Split(timeStr, ":")
-	if len(parts) == 2 {
-		minutes, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return 0, fmt.Errorf("invalid minutes")
-		}
-
-		secondsParts := strings.Split(parts[1], ".")
-		if len(secondsParts) != 2 {
-			return 0, fmt.Errorf("invalid seconds format")
-		}
-
-		seconds, err := strconv.Atoi(secondsParts[0])
-		if err != nil {
-			return 0, fmt.Errorf("invalid seconds")
-		}
-
-		tenths, err := strconv.Atoi(secondsParts[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid tenths")
+	// Anything that is not a lane on this course unassigns the capture
+	laneNum, err := strconv.Atoi(text)
+	if err != nil || !a.validLane(laneNum) {
+		laneNum = 0
+	}
+	if err := a.session.AssignLane(index, laneNum); err != nil {
+		// Duplicate lane, clear the input
+		a.session.AssignLane(index, 0)
+		if row := a.lapRow(index); row != nil {
+			a.refreshing = true
+			row.oofEntry.SetText(emptyString)
+			a.refreshing = false
 		}
-
-		return time.Duration(minutes)*time.Minute +
-			time.Duration(seconds)*time.Second +
-			time.Duration(tenths)*100*time.Millisecond, nil
 	}
+	a.refreshContent()
+}
 
-	// Try parsing as "00:00:00.000" format
-	if len(parts) == 3 {
-		hours, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return 0, fmt.Errorf("invalid hours")
-		}
+// focusNextOOF moves focus to the next capture's OOF entry
+func (a *App) focusNextOOF(index int) {
+	if a.session.State() == timing.StateRunning {
+		return
+	}
+	next := index + 1
+	if next >= len(a.snapshot.Captures) {
+		return
+	}
+	a.captureList.ScrollTo(next)
+	if row := a.lapRow(next); row != nil {
+		// Clear any existing text in the next entry
+		row.oofEntry.SetText(emptyString)
+		a.window.Canvas().Focus(row.oofEntry)
+	}
+}
 
-		minutes, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid minutes")
-		}
+// editPlace lets the user mark the lane of a lap table row DNS, DNF or DQ,
+// put it back into finish order with Next Place, or declare a dead heat with
+// the capture before it when their times are the same
+func (a *App) editPlace(row int) {
+	results := a.session.Results()
+	if results.State == timing.StateRunning || row >= len(results.Captures) {
+		return
+	}
+	capture := results.Captures[row]
+	laneNum := capture.Lane
+	if laneNum == 0 {
+		return // Don't allow editing if no lane is assigned
+	}
 
-		secondsParts := strings.Split(parts[2], ".")
-		if len(secondsParts) != 2 {
-			return 0, fmt.Errorf("invalid seconds format")
+	options := []string{string(timing.PlaceDNS), string(timing.PlaceDNF), string(timing.PlaceDQ), nextPlace}
+	if capture.CanDeadHeat || capture.DeadHeat {
+		options = append(options, deadHeat)
+	}
+	selectWidget := widget.NewSelect(options, func(value string) {
+		var err error
+		switch value {
+		case deadHeat:
+			err = a.session.SetDeadHeat(row, true)
+		case nextPlace:
+			if err = a.session.SetPlaceCode(laneNum, timing.PlaceNone); err == nil {
+				err = a.session.SetDeadHeat(row, false)
+			}
+		default:
+			err = a.session.SetPlaceCode(laneNum, timing.PlaceCode(value))
 		}
-
-		seconds, err := strconv.Atoi(secondsParts[0])
 		if err != nil {
-			return 0, fmt.Errorf("invalid seconds")
+			dialog.ShowError(err, a.window)
+			return
 		}
+		a.refreshContent()
+	})
 
-		milliseconds, err := strconv.Atoi(secondsParts[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid milliseconds")
+	// Set the current value if it is a place code or dead heat
+	currentPlace := results.Lane(laneNum).Place
+	if capture.DeadHeat {
+		currentPlace = deadHeat
+	}
+	for _, option := range options {
+		if option == currentPlace {
+			selectWidget.SetSelected(option)
+			break
 		}
-
-		return time.Duration(hours)*time.Hour +
-			time.Duration(minutes)*time.Minute +
-			time.Duration(seconds)*time.Second +
-			time.Duration(milliseconds)*time.Millisecond, nil
 	}
 
-	return 0, fmt.Errorf("invalid time format")
+	dialog.ShowCustom(
+		"Edit Place",
+		"Close",
+		selectWidget,
+		a.window,
+	)
 }
 
-// formatTime formats a time.Duration to "00:00.0"
-func formatTime(d time.Duration) string {
-	minutes := int(d.Minutes()) % 60
-	seconds := int(d.Seconds()) % 60
-	tenths := int(d.Milliseconds()/100) % 10
-	return fmt.Sprintf("%02d:%02d.%d", minutes, seconds, tenths)
+// editSplit corrects a capture's raw time from its split entry
+func (a *App) editSplit(row int, text string) {
+	if a.refreshing || a.session.State() == timing.StateRunning {
+		return
+	}
+	lapTime, err := timing.ParseDuration(text)
+	if err != nil {
+		return
+	}
+	if err := a.session.SetCaptureTime(row, lapTime); err != nil {
+		return
+	}
+	a.refreshContent()
 }
 
 func (a *App) showRaceTree() {
@@ -636,161 +421,190 @@ func (a *App) showRaceTree() {
 	mainContainer.Add(title)
 
 	// Create a list to hold the race nodes
-	raceList := container.NewVBox()
+	a.raceList = container.NewVBox()
+	a.fillRaceTree()
+
+	// Create a scroll container for the race list
+	scroll := container.NewScroll(a.raceList)
+	scroll.SetMinSize(fyne.NewSize(400, 400))
+	mainContainer.Add(scroll)
+
+	// Set the window content
+	a.window.SetContent(mainContainer)
+	a.window.Resize(fyne.NewSize(500, 600))
+}
+
+// fillRaceTree adds a row for every race to the race tree, grouped by day
+// or session
+func (a *App) fillRaceTree() {
+	raceList := a.raceList
+	raceList.RemoveAll()
 
-	// Sort races by race number
 	races := make([]RaceData, len(a.regattaData.Races))
 	copy(races, a.regattaData.Races)
-	sort.Slice(races, func(i, j int) bool {
-		return races[i].RaceNumber < races[j].RaceNumber
-	})
+	sortRaces(races)
 
-	// Add each race to the tree
-	for _, race := range races {
-		// Count non-empty school names
-		boatCount := 0
-		for _, lane := range race.Lanes {
-			if lane.SchoolName != "" {
-				boatCount++
-			}
+	// Group races by day or session when there is more than one
+	sessions := a.regattaData.Sessions()
+	for _, session := range sessions {
+		if len(sessions) > 1 {
+			sessionLabel := widget.NewLabel(session)
+			sessionLabel.TextStyle = fyne.TextStyle{Bold: true}
+			raceList.Add(sessionLabel)
 		}
 
-		// Get boat class and flight/heat/final information from RawData
-		boatClass := ""
-		flightInfo := ""
-		if len(race.RawData) > 0 {
-			if len(race.RawData[0]) > 0 {
-				boatClass = race.RawData[0][0]
-			}
-			if len(race.RawData) > 1 && len(race.RawData[1]) > 0 {
-				flightInfo = race.RawData[1][0]
+		for _, race := range races {
+			if race.Session != session {
+				continue
 			}
+			raceList.Add(a.raceTreeNode(race))
 		}
+	}
+	raceList.Refresh()
+}
 
-		// Create the race description
-		raceDesc := fmt.Sprintf("Race %d (%d boats)", race.RaceNumber, boatCount)
-		if boatClass != "" {
-			raceDesc = fmt.Sprintf("%s - %s", raceDesc, boatClass)
-		}
-		if flightInfo != "" {
-			raceDesc = fmt.Sprintf("%s - %s", raceDesc, flightInfo)
-		}
+// updateRaceTree redraws the race tree rows, such as when a race is approved
+// or protested
+func (a *App) updateRaceTree() {
+	if a.raceList == nil || a.regattaData == nil {
+		return
+	}
+	a.fillRaceTree()
+}
 
-		// Create a container for this race
-		raceContainer := container.NewHBox(
-			widget.NewLabel(raceDesc),
-			layout.NewSpacer(),
-		)
+// raceTreeNode creates the race tree row for a race with its Time Race button
+func (a *App) raceTreeNode(race RaceData) *fyne.Container {
+	// Get boat class and flight/heat/final information from RawData
+	boatClass := race.BoatClass()
+	flightInfo := race.Flight()
 
-		// Create a button to time this race
-		timeButton := widget.NewButton("Time Race", func(raceData RaceData) func() {
-			return func() {
-				a.openRaceClock(raceData)
-			}
-		}(race))
-		raceContainer.Add(timeButton)
+	// Create the race description
+	raceDesc := fmt.Sprintf("Race %d (%d boats)", race.RaceNumber, race.BoatCount())
+	if boatClass != "" {
+		raceDesc = fmt.Sprintf("%s - %s", raceDesc, boatClass)
+	}
+	if flightInfo != "" {
+		raceDesc = fmt.Sprintf("%s - %s", raceDesc, flightInfo)
+	}
+
+	// Create a container for this race
+	raceContainer := container.NewHBox(
+		widget.NewLabel(raceDesc),
+		layout.NewSpacer(),
+	)
 
-		raceList.Add(raceContainer)
+	// Show where the race's results stand
+	switch {
+	case race.OpenProtest() != nil:
+		status := widget.NewLabel(fmt.Sprintf("Protest: %s", race.OpenProtest().Reason))
+		status.Importance = widget.DangerImportance
+		raceContainer.Add(status)
+	case race.Approved && race.Amended():
+		status := widget.NewLabel(fmt.Sprintf("Amended v%d", race.ResultsVersion()))
+		status.Importance = widget.WarningImportance
+		raceContainer.Add(status)
+	case race.Approved:
+		raceContainer.Add(widget.NewLabel("Approved"))
 	}
 
-	// Create a scroll container for the race list
-	scroll := container.NewScroll(raceList)
-	scroll.SetMinSize(fyne.NewSize(400, 400))
-	mainContainer.Add(scroll)
+	// Create a button to time this race
+	timeButton := widget.NewButton("Time Race", func(raceData RaceData) func() {
+		return func() {
+			a.openRaceClock(raceData)
+		}
+	}(race))
+	raceContainer.Add(timeButton)
 
-	// Set the window content
-	a.window.SetContent(mainContainer)
-	a.window.Resize(fyne.NewSize(500, 600))
+	// Approved results can be reopened for a protest or correction
+	protestButton := widget.NewButton("Protest", func() {
+		a.showProtest(race.Key())
+	})
+	if !race.Approved {
+		protestButton.Disable()
+	}
+	raceContainer.Add(protestButton)
+
+	return raceContainer
 }
 
 func (a *App) openRaceClock(race RaceData) {
+	if raceApp, open := a.raceWindows[race.Key()]; open {
+		raceApp.window.RequestFocus()
+		return
+	}
+	// Carry on with the race's last session, such as one opened from a project
+	if log, timed := a.raceLogs[race.Key()]; timed {
+		if err := a.resumeRace(race, log.Events()); err != nil {
+			dialog.ShowError(err, a.window)
+		}
+		return
+	}
+
+	session := timing.NewRaceSessionWithClock(race.NumLanes(), a.timeSource)
+
+	// Journal the session from the start so a crash loses nothing
+	journal, journalErr := a.startJournal(race)
+	log := timing.NewEventLog(nil, journalRecorder(journal))
+	open, err := openEvent(race)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	log.Record(open)
+	session.SetRecorder(log)
+	session.SetRules(a.timingRules())
+	if a.regattaData != nil {
+		session.SetSplitStations(a.regattaData.Splits)
+	}
+
+	raceApp := a.showRaceClock(race, session, log, journal)
+	if journalErr != nil {
+		raceApp.warnNotJournaled(journalErr)
+	}
+}
+
+// showRaceClock opens a race window timing race with session, which records
+// its changes to log and, if it is not nil, journal
+func (a *App) showRaceClock(race RaceData, session *timing.RaceSession, log *timing.EventLog, journal *timing.Journal) *App {
 	// Create a new window for this race
-	raceWindow := a.app.NewWindow(fmt.Sprintf("Race %d Clock", race.RaceNumber))
+	raceWindow := a.app.NewWindow(fmt.Sprintf("%s Clock", race.Key().Label()))
 
 	// Create a new App instance for this race
 	raceApp := &App{
-		window:   raceWindow,
-		app:      a.app,
-		lapTimes: make([]lapTime, 0),
-		clockState: &clockState{
-			isRunning: false,
-			isCleared: true,
-			stopChan:  make(chan struct{}),
-		},
+		window:      raceWindow,
+		app:         a.app,
+		session:     session,
+		raceLog:     log,
+		journal:     journal,
+		timeSource:  a.timeSource,
+		laneCount:   race.NumLanes(),
+		stopChan:    make(chan struct{}),
 		regattaData: a.regattaData,
+		timedRace:   race.Key(),
 	}
+	raceApp.raceTreeChanged = a.updateRaceTree
 
 	// Initialize the app data (this sets up all necessary widgets)
 	raceApp.initAppData()
 
 	// Initialize the clock specifically for this window
-	raceApp.clock = canvas.NewText(zeroTime, color.White)
+	raceApp.clock = canvas.NewText(raceApp.zeroTime(), color.White)
 	raceApp.clock.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
 	raceApp.clock.Alignment = fyne.TextAlignCenter
 	raceApp.clock.TextSize = 48
 
-	// Count the number of boats in the race
-	boatCount := 0
-	for _, entry := range race.Lanes {
-		if entry.SchoolName != "" {
-			boatCount++
-		}
-	}
-
-	// Get boat class and flight/heat/final information from RawData
-	boatClass := ""
-	flightInfo := ""
-	if len(race.RawData) > 0 {
-		if len(race.RawData[0]) > 0 {
-			boatClass = race.RawData[0][0]
-		}
-		if len(race.RawData) > 1 && len(race.RawData[1]) > 0 {
-			flightInfo = race.RawData[1][0]
-		}
-	}
-
 	// Create the race title text
-	titleText := fmt.Sprintf("Race %d (%d Boats)", race.RaceNumber, boatCount)
-	if boatClass != "" {
-		titleText = fmt.Sprintf("%s - %s", titleText, boatClass)
-	}
-	if flightInfo != "" {
-		titleText = fmt.Sprintf("%s - %s", titleText, flightInfo)
-	}
-
-	title := canvas.NewText(titleText, color.White)
+	title := canvas.NewText(race.Title(), color.White)
 	title.TextStyle = fyne.TextStyle{Bold: true}
 	title.Alignment = fyne.TextAlignCenter
 	title.TextSize = 48
 
 	// Initialize the results table with the race data
-	raceApp.resultsTable = make([][]string, 6)
-	for i := range raceApp.resultsTable {
-		raceApp.resultsTable[i] = make([]string, 7)
-	}
-
-	// Set up the results table headers
-	raceApp.resultsTable[0][0] = ""
-	raceApp.resultsTable[1][0] = ""
-	raceApp.resultsTable[2][0] = ""
-	raceApp.resultsTable[3][0] = "Place"
-	raceApp.resultsTable[4][0] = "Split"
-	raceApp.resultsTable[5][0] = "Time"
-
-	// Always show all lane headers (1-6)
-	for lane := 1; lane <= 6; lane++ {
-		raceApp.resultsTable[0][lane] = fmt.Sprintf("Lane %d", lane)
-		// Initialize empty strings for all other columns
-		raceApp.resultsTable[1][lane] = ""
-		raceApp.resultsTable[2][lane] = ""
-		raceApp.resultsTable[3][lane] = ""
-		raceApp.resultsTable[4][lane] = ""
-		raceApp.resultsTable[5][lane] = ""
-	}
+	raceApp.resultsTable = newResultsTable(raceApp.laneCount, session.SplitStations())
 
 	// Populate school data for scheduled lanes
 	for lane, entry := range race.Lanes {
-		if lane >= 1 && lane <= 6 {
+		if raceApp.validLane(lane) {
 			// Set school name
 			raceApp.resultsTable[1][lane] = entry.SchoolName
 			// Set additional info
@@ -814,10 +628,27 @@ func (a *App) openRaceClock(race RaceData) {
 	refereeButton.Disable() // Initially disabled until winning time is set
 
 	saveButton := widget.NewButton("Save", func() {
-		// Save logic will be implemented later
+		raceApp.saveRace(race)
 	})
 	saveButton.Disable() // Initially disabled until approved
 
+	pdfButton := widget.NewButton("Results PDF", func() {
+		raceApp.exportRacePDF(race)
+	})
+	pdfButton.Disable() // Initially disabled until approved
+
+	correctStartButton := widget.NewButton("Correct Start", func() {
+		raceApp.showStartCorrection()
+	})
+
+	insertButton := widget.NewButton("Insert Capture", func() {
+		raceApp.insertCapture()
+	})
+
+	historyButton := widget.NewButton("History", func() {
+		raceApp.showHistory()
+	})
+
 	// Create a container for the buttons
 	buttonContainer := container.NewHBox(
 		layout.NewSpacer(),
@@ -825,6 +656,14 @@ func (a *App) openRaceClock(race RaceData) {
 		layout.NewSpacer(),
 		saveButton,
 		layout.NewSpacer(),
+		pdfButton,
+		layout.NewSpacer(),
+		correctStartButton,
+		layout.NewSpacer(),
+		insertButton,
+		layout.NewSpacer(),
+		historyButton,
+		layout.NewSpacer(),
 	)
 
 	// Create the final content with all elements
@@ -839,92 +678,44 @@ func (a *App) openRaceClock(race RaceData) {
 
 	// Set up keyboard handler for this window
 	raceWindow.Canvas().SetOnTypedKey(raceApp.setupKeyboardHandler())
+	raceApp.setupUndoShortcuts(raceWindow.Canvas())
 
 	// Start the clock update goroutine for this window
 	go raceApp.startClockUpdate()
 
-	// Set up window close handler to clean up the goroutine
+	// Set up window close handler to clean up the goroutine and finish the journal
 	raceWindow.SetOnClosed(func() {
-		close(raceApp.clockState.stopChan)
+		close(raceApp.stopChan)
+		if raceApp.historyWindow != nil {
+			raceApp.historyWindow.Close()
+		}
+		if err := raceApp.closeJournal(); err != nil {
+			dialog.ShowError(err, a.window)
+		}
+		delete(a.raceWindows, race.Key())
 	})
 
+	a.raceLogs[race.Key()] = log
+	a.raceWindows[race.Key()] = raceApp
 	raceWindow.Show()
+	return raceApp
 }
 
 // showRefereeApproval creates and shows the referee approval window
 func (a *App) showRefereeApproval(race RaceData) {
 	// Create a new window for referee approval
-	approvalWindow := a.app.NewWindow(fmt.Sprintf("Referee Approval - Race %d", race.RaceNumber))
+	approvalWindow := a.app.NewWindow(fmt.Sprintf("Referee Approval - %s", race.Key().Label()))
 
 	// Create the title
-	boatCount := 0
-	for _, entry := range race.Lanes {
-		if entry.SchoolName != "" {
-			boatCount++
-		}
-	}
-
-	// Get boat class and flight/heat/final information from RawData
-	boatClass := ""
-	flightInfo := ""
-	if len(race.RawData) > 0 {
-		if len(race.RawData[0]) > 0 {
-			boatClass = race.RawData[0][0]
-		}
-		if len(race.RawData) > 1 && len(race.RawData[1]) > 0 {
-			flightInfo = race.RawData[1][0]
-		}
-	}
-
-	// Create the race title text
-	titleText := fmt.Sprintf("Race %d (%d Boats)", race.RaceNumber, boatCount)
-	if boatClass != "" {
-		titleText = fmt.Sprintf("%s - %s", titleText, boatClass)
-	}
-	if flightInfo != "" {
-		titleText = fmt.Sprintf("%s - %s", titleText, flightInfo)
-	}
-
-	title := canvas.NewText(titleText, color.White)
+	title := canvas.NewText(race.Title(), color.White)
 	title.TextStyle = fyne.TextStyle{Bold: true}
 	title.Alignment = fyne.TextAlignCenter
 	title.TextSize = 48
 
 	// Create the table data
 	tableData := make([][]string, 0)
-	headers := []string{"OOF", "Place", "Split", "Time", "School"}
-	tableData = append(tableData, headers)
-
-	// First add numerical places in order
-	for i := 1; i <= 6; i++ {
-		for lane := 1; lane <= 6; lane++ {
-			if a.resultsTable[3][lane] == fmt.Sprintf("%d", i) {
-				row := []string{
-					fmt.Sprintf("%d", lane),
-					a.resultsTable[3][lane],
-					a.resultsTable[4][lane],
-					a.resultsTable[5][lane],
-					a.resultsTable[1][lane],
-				}
-				tableData = append(tableData, row)
-			}
-		}
-	}
-
-	// Then add DQ/DNS/DNF entries
-	for lane := 1; lane <= 6; lane++ {
-		place := a.resultsTable[3][lane]
-		if place == "DQ" || place == "DNS" || place == "DNF" {
-			row := []string{
-				fmt.Sprintf("Lane %d", lane),
-				place,
-				a.resultsTable[4][lane],
-				a.resultsTable[5][lane],
-				a.resultsTable[1][lane],
-			}
-			tableData = append(tableData, row)
-		}
-	}
+	tableData = append(tableData, resultSheetHeaders)
+	tableData = append(tableData, RaceResultRows(a.resultsRace(race))...)
 
 	// Create the table using a grid layout
 	table := container.NewGridWithColumns(5)
@@ -967,25 +758,57 @@ func (a *App) showRefereeApproval(race RaceData) {
 		}
 	}
 
+	// Show split and segment times below the results when the race has split stations
+	splits := splitsTable(a.session.Results(), race)
+
+	// The referee signs the approval, with an optional note
+	refereeEntry := widget.NewEntry()
+	refereeEntry.SetPlaceHolder("Name or initials")
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("Optional")
+	approved := a.findRace(race.Key())
+	if approved != nil {
+		if previous := approved.LastApproval(); previous != nil {
+			refereeEntry.SetText(previous.Referee)
+		}
+	}
+	signature := widget.NewForm(
+		widget.NewFormItem("Referee", refereeEntry),
+		widget.NewFormItem("Note", noteEntry),
+	)
+
+	// Show what changed since the results were last approved
+	var amendment *fyne.Container
+	if approved != nil {
+		if previous := approved.LastApproval(); previous != nil {
+			amendment = container.NewVBox()
+			if protest := approved.OpenProtest(); protest != nil {
+				amendment.Add(widget.NewLabelWithStyle(fmt.Sprintf("Under review: %s", protest.Reason),
+					fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			}
+			amendment.Add(widget.NewLabelWithStyle(
+				fmt.Sprintf("Changes since version %d was approved by %s at %s",
+					approved.ResultsVersion(), previous.Referee, previous.At.Format("15:04:05")),
+				fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			changes := DiffResults(previous.Results, tableData[1:])
+			if len(changes) == 0 {
+				amendment.Add(widget.NewLabel("No changes"))
+			}
+			for _, change := range changes {
+				amendment.Add(widget.NewLabel(change.String()))
+			}
+		}
+	}
+
 	// Create the action buttons
 	approveButton := widget.NewButton("Approve", func() {
-		// Find the race in regattaData and set its Approved flag
-		for i := range a.regattaData.Races {
-			if a.regattaData.Races[i].RaceNumber == race.RaceNumber {
-				a.regattaData.Races[i].Approved = true
-				// Find and enable the Save button in the main window
-				for _, content := range a.window.Content().(*fyne.Container).Objects {
-					if buttonContainer, ok := content.(*fyne.Container); ok {
-						for _, button := range buttonContainer.Objects {
-							if saveButton, ok := button.(*widget.Button); ok && saveButton.Text == "Save" {
-								saveButton.Enable()
-								break
-							}
-						}
-					}
-				}
-				break
-			}
+		if approved == nil {
+			dialog.ShowError(fmt.Errorf("%s not found in regatta data", race.Key()), approvalWindow)
+			return
+		}
+		if err := a.approveRace(approved, refereeEntry.Text, noteEntry.Text); err != nil {
+			dialog.ShowError(err, approvalWindow)
+			return
 		}
 		approvalWindow.Close()
 	})
@@ -1007,8 +830,15 @@ func (a *App) showRefereeApproval(race RaceData) {
 	content := container.NewVBox(
 		container.NewCenter(title),
 		table,
-		buttonContainer,
 	)
+	if splits != nil {
+		content.Add(splits)
+	}
+	if amendment != nil {
+		content.Add(amendment)
+	}
+	content.Add(signature)
+	content.Add(buttonContainer)
 
 	approvalWindow.SetContent(content)
 	approvalWindow.Resize(fyne.NewSize(1000, 800))
@@ -1017,86 +847,85 @@ func (a *App) showRefereeApproval(race RaceData) {
 
 func (a *App) setupWinningTime() {
 	a.winningTime = widget.NewEntry()
-	a.winningTime.SetPlaceHolder("00:00.0")
+	a.winningTime.SetPlaceHolder(a.zeroTime())
 	a.winningTime.OnChanged = func(text string) {
-		// If winning time is empty, just disable referee button
+		if a.refreshing {
+			return
+		}
+		// If winning time is empty, remove the calibration
 		if text == "" {
-			// Find and disable the referee button
-			for _, content := range a.window.Content().(*fyne.Container).Objects {
-				if buttonContainer, ok := content.(*fyne.Container); ok {
-					for _, button := range buttonContainer.Objects {
-						if refereeButton, ok := button.(*widget.Button); ok && refereeButton.Text == "Referee Approval" {
-							refereeButton.Disable()
-							break
-						}
-					}
-				}
-			}
-			// Clear all results table times
-			for i := 1; i <= 6; i++ {
-				a.resultsTable[5][i] = emptyString
-			}
-			a.window.Content().Refresh() // Refresh the window
+			a.session.ClearWinningTime()
+			a.refreshContent()
+			a.updateRefereeButton()
 			return
 		}
 
 		// Try to parse the winning time
-		winningTime, err := parseTime(text)
+		winningTime, err := timing.ParseDuration(text)
 		if err != nil {
 			// Invalid time format, disable referee button
-			for _, content := range a.window.Content().(*fyne.Container).Objects {
-				if buttonContainer, ok := content.(*fyne.Container); ok {
-					for _, button := range buttonContainer.Objects {
-						if refereeButton, ok := button.(*widget.Button); ok && refereeButton.Text == "Referee Approval" {
-							refereeButton.Disable()
-							break
-						}
-					}
-				}
-			}
+			a.session.ClearWinningTime()
+			a.updateRefereeButton()
 			return
 		}
 
-		// If we have a valid winning time and at least one lap time, enable the referee button
-		if len(a.lapTimes) > 0 {
-			firstLapTime, err := parseTime(a.lapTimes[0].time)
-			if err == nil {
-				// Calculate the time adjustment
-				timeAdjustment := winningTime - firstLapTime
-
-				// Update all lap times and results table
-				for i := 0; i < len(a.lapTimes); i++ {
-					lapTime, err := parseTime(a.lapTimes[i].time)
-					if err == nil {
-						adjustedTime := lapTime + timeAdjustment
-						adjustedTimeStr := formatTime(adjustedTime)
-						a.lapTimes[i].calculatedTime = adjustedTimeStr
-
-						// Update results table if OOF is set
-						if oof := a.lapTimes[i].oof; oof != emptyString {
-							if laneNum, err := strconv.Atoi(oof); err == nil && laneNum >= 1 && laneNum <= 6 {
-								a.resultsTable[5][laneNum] = adjustedTimeStr
-							}
-						}
-					}
-				}
+		a.session.SetWinningTime(winningTime)
+		a.refreshContent() // Refresh content when winning time is set
+		a.updateRefereeButton()
+	}
+}
 
-				// Find and enable the referee button
-				for _, content := range a.window.Content().(*fyne.Container).Objects {
-					if buttonContainer, ok := content.(*fyne.Container); ok {
-						for _, button := range buttonContainer.Objects {
-							if refereeButton, ok := button.(*widget.Button); ok && refereeButton.Text == "Referee Approval" {
-								refereeButton.Enable()
-								break
-							}
-						}
-					}
+// updateRefereeButton enables Referee Approval once the race is stopped with
+// at least one capture and a winning time
+func (a *App) updateRefereeButton() {
+	refereeButton := a.actionButton("Referee Approval")
+	if refereeButton == nil {
+		return
+	}
+	results := a.session.Results()
+	if results.State == timing.StateStopped && results.Calibrated && len(results.Captures) > 0 {
+		refereeButton.Enable()
+	} else {
+		refereeButton.Disable()
+	}
+}
+
+// enableApprovedActions enables the Save and Results PDF buttons of an
+// approved race
+func (a *App) enableApprovedActions() {
+	for _, text := range []string{"Save", "Results PDF"} {
+		if button := a.actionButton(text); button != nil {
+			button.Enable()
+		}
+	}
+}
+
+// disableApprovedActions disables the Save and Results PDF buttons of a race
+// whose results need approving again
+func (a *App) disableApprovedActions() {
+	for _, text := range []string{"Save", "Results PDF"} {
+		if button := a.actionButton(text); button != nil {
+			button.Disable()
+		}
+	}
+}
+
+// actionButton finds a button in the window's button rows by its text
+func (a *App) actionButton(text string) *widget.Button {
+	content, ok := a.window.Content().(*fyne.Container)
+	if !ok {
+		return nil
+	}
+	for _, object := range content.Objects {
+		if buttonContainer, ok := object.(*fyne.Container); ok {
+			for _, button := range buttonContainer.Objects {
+				if actionButton, ok := button.(*widget.Button); ok && actionButton.Text == text {
+					return actionButton
 				}
-				a.refreshContent()           // Refresh content when winning time is set
-				a.window.Content().Refresh() // Refresh the window
 			}
 		}
 	}
+	return nil
 }
 
 func (a *App) setupResultsTable() *widget.Table {
@@ -1116,7 +945,7 @@ func (a *App) setupResultsTable() *widget.Table {
 
 	// Set column widths
 	resultsTable.SetColumnWidth(0, 100) // Lane
-	for i := 1; i <= 6; i++ {
+	for i := 1; i <= a.laneCount; i++ {
 		resultsTable.SetColumnWidth(i, 150) // Lane times
 	}
 
@@ -0,0 +1,208 @@
+package regattaClock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// Approval is a referee's sign-off of a race's results, kept as an audit
+// record of who approved what and when
+type Approval struct {
+	Referee string         // Name or initials
+	At      time.Time      // When the results were approved
+	Note    string         // Optional note from the referee
+	Results [][]string     // Results rows as approved, in resultSheetHeaders order
+	Changes []ResultChange // Amendment approved, since the previous approval
+}
+
+// ResultChange is a lane result that differs between two sets of results
+type ResultChange struct {
+	Lane   string // Lane as shown in the OOF column
+	Field  string // Results column heading
+	Before string
+	After  string
+}
+
+// String describes the change, such as "Lane 3 Place: 2 → 3"
+func (c ResultChange) String() string {
+	blank := func(text string) string {
+		if text == emptyString {
+			return "(blank)"
+		}
+		return text
+	}
+	return fmt.Sprintf("Lane %s %s: %s → %s", c.Lane, c.Field, blank(c.Before), blank(c.After))
+}
+
+// DiffResults returns the changes from one set of results rows to another,
+// matching rows by lane
+func DiffResults(before, after [][]string) []ResultChange {
+	byLane := func(rows [][]string) map[string][]string {
+		lanes := make(map[string][]string)
+		for _, row := range rows {
+			if len(row) > 0 {
+				lanes[row[0]] = row
+			}
+		}
+		return lanes
+	}
+	beforeLanes := byLane(before)
+	afterLanes := byLane(after)
+
+	lanes := make([]string, 0, len(beforeLanes)+len(afterLanes))
+	for lane := range beforeLanes {
+		lanes = append(lanes, lane)
+	}
+	for lane := range afterLanes {
+		if _, exists := beforeLanes[lane]; !exists {
+			lanes = append(lanes, lane)
+		}
+	}
+	sort.Slice(lanes, func(i, j int) bool {
+		li, _ := strconv.Atoi(lanes[i])
+		lj, _ := strconv.Atoi(lanes[j])
+		return li < lj
+	})
+
+	cell := func(row []string, col int) string {
+		if col < len(row) {
+			return row[col]
+		}
+		return emptyString
+	}
+	changes := make([]ResultChange, 0)
+	for _, lane := range lanes {
+		for col := 1; col < len(resultSheetHeaders); col++ {
+			was := cell(beforeLanes[lane], col)
+			now := cell(afterLanes[lane], col)
+			if was != now {
+				changes = append(changes, ResultChange{Lane: lane, Field: resultSheetHeaders[col], Before: was, After: now})
+			}
+		}
+	}
+	return changes
+}
+
+// LastApproval returns the race's most recent approval, or nil if it has
+// never been approved
+func (r RaceData) LastApproval() *Approval {
+	if len(r.Approvals) == 0 {
+		return nil
+	}
+	return &r.Approvals[len(r.Approvals)-1]
+}
+
+// Describe says who approved the results and when, such as
+// "Approved by JS at 14:05:32"
+func (a Approval) Describe() string {
+	text := fmt.Sprintf("Approved by %s at %s", a.Referee, a.At.Format("15:04:05"))
+	if a.Note != emptyString {
+		text = fmt.Sprintf("%s: %s", text, a.Note)
+	}
+	return text
+}
+
+// findRace returns the regatta's race with the given key, or nil
+func (a *App) findRace(key RaceKey) *RaceData {
+	if a.regattaData == nil {
+		return nil
+	}
+	for i := range a.regattaData.Races {
+		if a.regattaData.Races[i].Key() == key {
+			return &a.regattaData.Races[i]
+		}
+	}
+	return nil
+}
+
+// approveRace records the referee's approval of the race window's results
+// and marks the race approved
+func (a *App) approveRace(race *RaceData, referee string, note string) error {
+	referee = strings.TrimSpace(referee)
+	if referee == emptyString {
+		return fmt.Errorf("enter the referee's name or initials")
+	}
+
+	approval := Approval{
+		Referee: referee,
+		At:      a.session.Now(),
+		Note:    strings.TrimSpace(note),
+		Results: RaceResultRows(a.resultsRace(*race)),
+	}
+	if previous := race.LastApproval(); previous != nil {
+		approval.Changes = DiffResults(previous.Results, approval.Results)
+	}
+	meta, err := json.Marshal(approval)
+	if err != nil {
+		return fmt.Errorf("failed to record approval: %v", err)
+	}
+
+	a.storeResults(race)
+	closeProtest(race, approval)
+	race.Approvals = append(race.Approvals, approval)
+	race.Approved = true
+	a.amended = false
+	a.recordEvent(timing.Event{Kind: timing.EventApproved, At: approval.At, Meta: meta})
+	a.enableApprovedActions()
+	if a.raceTreeChanged != nil {
+		a.raceTreeChanged()
+	}
+	return nil
+}
+
+// checkAmendment compares an approved race's results with those the referee
+// approved. An edit after approval amends the results, which then need
+// approving again; undoing the edit withdraws the amendment.
+func (a *App) checkAmendment() {
+	race := a.findRace(a.timedRace)
+	if race == nil {
+		return
+	}
+	approval := race.LastApproval()
+	if approval == nil {
+		return
+	}
+
+	changes := DiffResults(approval.Results, RaceResultRows(a.resultsRace(*race)))
+	switch {
+	case race.Approved && len(changes) > 0:
+		race.Approved = false
+		a.amended = true
+		a.disableApprovedActions()
+		a.updateRefereeButton()
+
+		lines := make([]string, len(changes))
+		for i, change := range changes {
+			lines[i] = change.String()
+		}
+		message := fmt.Sprintf("%s was approved by %s at %s.\nIts results have changed and need approving again:\n\n%s",
+			race.Key().Label(), approval.Referee, approval.At.Format("15:04:05"), strings.Join(lines, "\n"))
+		dialog.ShowInformation("Results Amended", message, a.window)
+	case a.amended && !race.Approved && len(changes) == 0:
+		race.Approved = true
+		a.amended = false
+		a.enableApprovedActions()
+	}
+}
+
+// approvalFromEvent reads the approval recorded with an approved event.
+// Events recorded before approvals were kept have no record, so the results
+// shown are taken as those approved.
+func (a *App) approvalFromEvent(race RaceData, event timing.Event) Approval {
+	var approval Approval
+	if len(event.Meta) > 0 {
+		if err := json.Unmarshal(event.Meta, &approval); err == nil {
+			// The event's time is on the replayed session's timeline
+			approval.At = event.At
+			return approval
+		}
+	}
+	return Approval{At: event.At, Results: RaceResultRows(a.resultsRace(race))}
+}
@@ -0,0 +1,94 @@
+package regattaClock
+
+import "testing"
+
+func TestDiffResults(t *testing.T) {
+	// Rows are OOF, Place, Split, Time and School, as in the results sheet
+	approved := [][]string{
+		{"3", "1", "", "07:01.2", "Alpha"},
+		{"1", "2", "1.8", "07:03.0", "Bravo"},
+	}
+
+	tests := []struct {
+		name   string
+		before [][]string
+		after  [][]string
+		want   []string
+	}{
+		{name: "unchanged", before: approved, after: approved},
+		{name: "no results", before: nil, after: nil},
+		{
+			name:   "reordered rows",
+			before: approved,
+			after:  [][]string{approved[1], approved[0]},
+		},
+		{
+			name:   "time corrected",
+			before: approved,
+			after: [][]string{
+				{"3", "1", "", "07:01.4", "Alpha"},
+				{"1", "2", "1.8", "07:03.0", "Bravo"},
+			},
+			want: []string{"Lane 3 Time: 07:01.2 → 07:01.4"},
+		},
+		{
+			name:   "places swapped",
+			before: approved,
+			after: [][]string{
+				{"3", "2", "1.8", "07:03.0", "Alpha"},
+				{"1", "1", "", "07:01.2", "Bravo"},
+			},
+			want: []string{
+				"Lane 1 Place: 2 → 1",
+				"Lane 1 Split: 1.8 → (blank)",
+				"Lane 1 Time: 07:03.0 → 07:01.2",
+				"Lane 3 Place: 1 → 2",
+				"Lane 3 Split: (blank) → 1.8",
+				"Lane 3 Time: 07:01.2 → 07:03.0",
+			},
+		},
+		{
+			name:   "lane added",
+			before: approved,
+			after:  append([][]string{{"10", "DNF", "", "", "Charlie"}}, approved...),
+			want: []string{
+				"Lane 10 Place: (blank) → DNF",
+				"Lane 10 School: (blank) → Charlie",
+			},
+		},
+		{
+			name:   "lane removed",
+			before: approved,
+			after:  approved[:1],
+			want: []string{
+				"Lane 1 Place: 2 → (blank)",
+				"Lane 1 Split: 1.8 → (blank)",
+				"Lane 1 Time: 07:03.0 → (blank)",
+				"Lane 1 School: Bravo → (blank)",
+			},
+		},
+		{
+			name:   "short row",
+			before: approved,
+			after: [][]string{
+				{"3", "1", "", "07:01.2"},
+				{"1", "2", "1.8", "07:03.0", "Bravo"},
+			},
+			want: []string{"Lane 3 School: Alpha → (blank)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffResults(tt.before, tt.after)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("DiffResults() = %v, want %v", changes, tt.want)
+			}
+			for i, change := range changes {
+				if got := change.String(); got != tt.want[i] {
+					t.Errorf("change %d = %q, want %q", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package regattaClock
 
 import (
-	"fmt"
-	"time"
-
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
@@ -15,6 +12,8 @@ func (a *App) buttonPanel() *fyne.Container {
 		layout.NewSpacer(),
 		a.startButton(),
 		layout.NewSpacer(),
+		a.startSignalButton(),
+		layout.NewSpacer(),
 		a.lapButton(),
 		layout.NewSpacer(),
 		a.stopButton(),
@@ -33,23 +32,22 @@ func (a *App) startButton() *widget.Button {
 
 func (a *App) startFunc() func() {
 	return func() {
-		if !a.clockState.isRunning && a.clockState.isCleared {
-			a.clockState.startTime = time.Now()
-			a.clockState.isRunning = true
-			a.clockState.isCleared = false
-			a.lapTimes = append(a.lapTimes, lapTime{
-				number:         1,
-				time:           zeroTime,
-				calculatedTime: zeroTime,
-				oof:            emptyString,
-			})
-			a.refreshContent()
-			a.raceNumber.Disable()
-			a.winningTime.Disable()
+		if err := a.session.Start(); err != nil {
+			return
 		}
+		a.refreshContent()
+		a.raceNumber.Disable()
+		a.winningTime.Disable()
 	}
 }
 
+func (a *App) startSignalButton() *widget.Button {
+	return widget.NewButton(
+		"Start Signal (F3)",
+		a.startSignalFunc(),
+	)
+}
+
 func (a *App) lapButton() *widget.Button {
 	return widget.NewButton(
 		"Lap (F4)",
@@ -59,46 +57,40 @@ func (a *App) lapButton() *widget.Button {
 
 func (a *App) lapFunc() func() {
 	return func() {
-		if a.clockState.isRunning {
-			elapsed := time.Since(a.clockState.startTime)
-			minutes := int(elapsed.Minutes()) % 60
-			seconds := int(elapsed.Seconds()) % 60
-			tenths := int(elapsed.Milliseconds()/100) % 10
-			formatted := fmt.Sprintf("%02d:%02d.%d", minutes, seconds, tenths)
-
-			a.lapTimes = append(a.lapTimes, lapTime{
-				number:         len(a.lapTimes) + 1,
-				time:           formatted,
-				calculatedTime: formatted,
-				oof:            emptyString,
-			})
-			a.refreshContent()
+		if _, err := a.session.Capture(); err != nil {
+			return
+		}
+		a.refreshContent()
+		if a.captureList != nil {
+			a.captureList.ScrollToBottom()
 		}
 	}
 }
 
 func (a *App) stopButton() *widget.Button {
 	return widget.NewButton("Stop", func() {
-		a.clockState.isRunning = false
+		if err := a.session.Stop(); err != nil {
+			return
+		}
 		a.refreshContent()
 		a.raceNumber.Enable()
 		a.winningTime.Enable()
+		a.updateRefereeButton()
 	})
 }
 
 func (a *App) clearButton() *widget.Button {
 	return widget.NewButton("Clear", func() {
-		if !a.clockState.isRunning {
-			a.clockState.isRunning = false
-			a.clock.Text = zeroTime
-			a.clock.Refresh()
-			a.lapTimes = make([]lapTime, 0)
-			a.winningTime.Text = emptyString
-			a.winningTime.Refresh()
-			a.refreshContent()
-			a.raceNumber.Enable()
-			a.winningTime.Enable()
-			a.clockState.isCleared = true
+		if err := a.session.Clear(); err != nil {
+			return
 		}
+		a.clock.Text = a.zeroTime()
+		a.clock.Refresh()
+		a.winningTime.Text = emptyString
+		a.winningTime.Refresh()
+		a.refreshContent()
+		a.raceNumber.Enable()
+		a.winningTime.Enable()
+		a.updateRefereeButton()
 	})
 }
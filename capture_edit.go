@@ -0,0 +1,83 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// deleteCapture asks before removing a lap table row's capture, such as a
+// stray lap press
+func (a *App) deleteCapture(row int) {
+	results := a.session.Results()
+	if row >= len(results.Captures) {
+		return
+	}
+	capture := results.Captures[row]
+
+	message := fmt.Sprintf("Delete capture %d at %s?", row+1, capture.Elapsed.Format(results.Rules.Precision))
+	if capture.Lane != 0 {
+		message = fmt.Sprintf("%s Lane %d will be unassigned.", message, capture.Lane)
+	}
+	dialog.ShowConfirm("Delete Capture", message, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := a.session.DeleteCapture(row); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		a.refreshContent()
+		a.updateRefereeButton()
+	}, a.window)
+}
+
+// moveCapture moves a lap table row's capture up or down the finish order
+func (a *App) moveCapture(row int, delta int) {
+	if err := a.session.MoveCapture(row, delta); err != nil {
+		return // Already at the top or bottom
+	}
+	a.refreshContent()
+}
+
+// insertCapture asks for the time of a finish the timer missed and adds it
+// to the capture list in time order
+func (a *App) insertCapture() {
+	if a.session.State() != timing.StateStopped {
+		dialog.ShowError(fmt.Errorf("stop the race before inserting a capture"), a.window)
+		return
+	}
+
+	timeEntry := widget.NewEntry()
+	timeEntry.SetPlaceHolder(a.zeroTime())
+	dialog.ShowForm(
+		"Insert Capture",
+		"Insert",
+		"Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Split", timeEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			elapsed, err := timing.ParseDuration(timeEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			index, err := a.session.InsertCapture(elapsed)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			a.refreshContent()
+			a.updateRefereeButton()
+			a.captureList.ScrollTo(index)
+			if row := a.lapRow(index); row != nil {
+				a.window.Canvas().Focus(row.oofEntry)
+			}
+		},
+		a.window,
+	)
+}
@@ -1,6 +1,7 @@
 package regattaClock
 
 const (
-	zeroTime          = "00:00.0"
-	emptyString       = ""
+	emptyString      = ""
+	defaultLaneCount = 6  // Lanes on a standard course
+	maxLaneCount     = 10 // Most lanes a regatta may use
 )
@@ -0,0 +1,247 @@
+package regattaClock
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// CSV draw schema
+//
+// A CSV draw has a header row followed by one row per lane entry. Column names
+// are matched case-insensitively and may appear in any order:
+//
+//	Race     required  race number (integer)
+//	Lane     required  lane number (1-10)
+//	School   optional  school or crew name
+//	Info     optional  additional information, e.g. event or crew letter
+//	Class    optional  boat class, e.g. "M-1x"
+//	Flight   optional  flight, heat or final
+//	Session  optional  day or session the race belongs to, e.g. "Saturday"
+//	Bow      optional  bow number when the session is timed as a head race
+//	Place    optional  previously recorded place
+//	Split    optional  previously recorded split
+//	Time     optional  previously recorded time
+//	Regatta  optional  regatta name, taken from the first non-empty value
+//	Date     optional  regatta date, taken from the first non-empty value
+//
+// A race is identified by its session and number, so each day may number its
+// races from 1 again. Class and Flight are taken from the first row of each
+// race that has them.
+// The regatta's lane count is the highest lane in the file, and at least 6.
+// Rows with neither a school nor additional info are skipped, matching the
+// Excel reader.
+const (
+	csvRace    = "race"
+	csvLane    = "lane"
+	csvSchool  = "school"
+	csvInfo    = "info"
+	csvClass   = "class"
+	csvFlight  = "flight"
+	csvSession = "session"
+	csvBow     = "bow"
+	csvPlace   = "place"
+	csvSplit   = "split"
+	csvTime    = "time"
+	csvRegatta = "regatta"
+	csvDate    = "date"
+)
+
+// ImportOptions selects how a draw workbook is read. CSV draws ignore them.
+type ImportOptions struct {
+	Layout *WorkbookLayout // Workbook layout, nil for the standard layout
+	Sheets []string        // Sheets to read, empty for every sheet
+}
+
+// ReadDrawFile reads a draw from an .xlsx workbook, using opts, or from a
+// .csv file
+func ReadDrawFile(filePath string, opts ImportOptions) (*RegattaData, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".xlsx":
+		return ReadExcelSheets(filePath, opts.Layout, opts.Sheets)
+	case ".csv":
+		return ReadCSVFile(filePath)
+	}
+	return nil, fmt.Errorf("only .xlsx and .csv files are supported")
+}
+
+// ReadCSVFile reads a CSV draw file and returns the regatta data
+func ReadCSVFile(filePath string) (*RegattaData, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %v", err)
+	}
+	defer file.Close()
+
+	return ReadCSV(file)
+}
+
+// ReadCSV reads a CSV draw and returns the regatta data
+func ReadCSV(r io.Reader) (*RegattaData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Map the header row to column indexes
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{csvRace, csvLane} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return emptyString
+	}
+
+	data := &RegattaData{
+		LaneCount: defaultLaneCount,
+		Timing:    timing.DefaultRules(),
+		Races:     make([]RaceData, 0),
+	}
+	races := make(map[RaceKey]*RaceData)
+	raceLines := make(map[RaceKey]int)
+	order := make([]RaceKey, 0) // Races in the order they first appear
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %v", line, err)
+		}
+
+		if data.RegattaName == emptyString {
+			data.RegattaName = field(record, csvRegatta)
+		}
+		if data.DateText == emptyString {
+			data.setDate(field(record, csvDate), fmt.Sprintf("line %d", line))
+		}
+
+		raceValue := field(record, csvRace)
+		if raceValue == emptyString {
+			continue // Blank line
+		}
+		raceNum, err := strconv.Atoi(raceValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid race number %q", line, raceValue)
+		}
+		lane, err := strconv.Atoi(field(record, csvLane))
+		if err != nil || lane < 1 || lane > maxLaneCount {
+			return nil, fmt.Errorf("line %d: invalid lane %q", line, field(record, csvLane))
+		}
+
+		key := RaceKey{Session: field(record, csvSession), Number: raceNum}
+		race, exists := races[key]
+		if !exists {
+			race = newCSVRace(raceNum, maxLaneCount)
+			race.Session = key.Session
+			races[key] = race
+			raceLines[key] = line
+			order = append(order, key)
+		}
+
+		// Class and flight live in column C of the Excel layout
+		if class := field(record, csvClass); class != emptyString && race.RawData[0][0] == emptyString {
+			race.RawData[0][0] = class
+		}
+		if flight := field(record, csvFlight); flight != emptyString && race.RawData[1][0] == emptyString {
+			race.RawData[1][0] = flight
+		}
+
+		entry := RaceEntry{
+			SchoolName:     field(record, csvSchool),
+			AdditionalInfo: field(record, csvInfo),
+			Place:          field(record, csvPlace),
+			Split:          field(record, csvSplit),
+			Time:           field(record, csvTime),
+		}
+		if bow := field(record, csvBow); bow != emptyString {
+			entry.Bow, err = strconv.Atoi(bow)
+			if err != nil || entry.Bow < 1 {
+				return nil, fmt.Errorf("line %d: invalid bow number %q", line, bow)
+			}
+		}
+
+		if _, taken := race.Lanes[lane]; taken {
+			data.addDiagnostic(SeverityError, fmt.Sprintf("line %d", line),
+				"%s lane %d is entered more than once", key, lane)
+		}
+
+		// Mirror the lane into the raw rows the way the Excel reader does
+		race.RawData[0][lane] = entry.SchoolName
+		race.RawData[1][lane] = entry.AdditionalInfo
+		race.RawData[2][lane] = entry.Place
+		race.RawData[3][lane] = entry.Split
+		race.RawData[4][lane] = entry.Time
+
+		// Only add the lane if it has a school name or additional info
+		if entry.SchoolName != emptyString || entry.AdditionalInfo != emptyString {
+			race.Lanes[lane] = entry
+			if lane > data.LaneCount {
+				data.LaneCount = lane
+			}
+			// The event may be given for the whole race by Class or Flight
+			if entry.SchoolName != emptyString && entry.AdditionalInfo == emptyString &&
+				race.BoatClass() == emptyString && race.Flight() == emptyString {
+				data.addDiagnostic(SeverityWarning, fmt.Sprintf("line %d", line),
+					"%s lane %d has a school but no event", key, lane)
+			}
+		}
+	}
+
+	// Trim the raw rows to the course width
+	for _, key := range order {
+		race := races[key]
+		race.LaneCount = data.LaneCount
+		for i := range race.RawData {
+			race.RawData[i] = race.RawData[i][:data.LaneCount+1]
+		}
+		data.Races = append(data.Races, *race)
+	}
+
+	sortRaces(data.Races)
+
+	data.checkRaceNumbers(func(race RaceData) string {
+		return fmt.Sprintf("line %d", raceLines[race.Key()])
+	})
+
+	return data, nil
+}
+
+// newCSVRace creates an empty race with RawData shaped like a 5-row Excel block
+// with room for laneCount lanes
+func newCSVRace(raceNum int, laneCount int) *RaceData {
+	race := &RaceData{
+		RaceNumber: raceNum,
+		Lanes:      make(map[int]RaceEntry),
+		RawData:    make([][]string, 5),
+	}
+	for i := range race.RawData {
+		race.RawData[i] = make([]string, laneCount+1) // Event column, then each lane
+	}
+	race.RawData[2][0] = "Place"
+	race.RawData[3][0] = "Split"
+	race.RawData[4][0] = "Time"
+	return race
+}
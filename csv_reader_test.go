@@ -0,0 +1,122 @@
+package regattaClock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantRaces []RaceKey // Races in order
+		wantLanes int       // Regatta lane count
+		wantDiags int
+		wantErr   bool
+	}{
+		{
+			name:      "draw",
+			csv:       "Race,Lane,School,Info\n2,1,Alpha,M1x\n1,1,Bravo,W1x\n1,2,Charlie,W1x\n",
+			wantRaces: []RaceKey{{Number: 1}, {Number: 2}},
+			wantLanes: defaultLaneCount,
+		},
+		{
+			name:      "columns in any order and case",
+			csv:       "school, LANE ,race,info\nAlpha,1,1,M1x\n",
+			wantRaces: []RaceKey{{Number: 1}},
+			wantLanes: defaultLaneCount,
+		},
+		{
+			name:      "wide course",
+			csv:       "Race,Lane,School,Info\n1,8,Alpha,M1x\n",
+			wantRaces: []RaceKey{{Number: 1}},
+			wantLanes: 8,
+		},
+		{
+			name:      "blank lines and empty lanes are skipped",
+			csv:       "Race,Lane,School,Info\n1,1,Alpha,M1x\n,,,\n1,2,,\n",
+			wantRaces: []RaceKey{{Number: 1}},
+			wantLanes: defaultLaneCount,
+		},
+		{
+			name:      "each session numbers its races",
+			csv:       "Race,Lane,School,Info,Session\n1,1,Alpha,M1x,Saturday\n1,1,Bravo,W1x,Sunday\n2,1,Charlie,M1x,Saturday\n",
+			wantRaces: []RaceKey{{"Saturday", 1}, {"Saturday", 2}, {"Sunday", 1}},
+			wantLanes: defaultLaneCount,
+		},
+		{
+			name:      "lane entered twice",
+			csv:       "Race,Lane,School,Info\n1,1,Alpha,M1x\n1,1,Bravo,M1x\n",
+			wantRaces: []RaceKey{{Number: 1}},
+			wantLanes: defaultLaneCount,
+			wantDiags: 1,
+		},
+		{
+			name:      "school without an event",
+			csv:       "Race,Lane,School\n1,1,Alpha\n",
+			wantRaces: []RaceKey{{Number: 1}},
+			wantLanes: defaultLaneCount,
+			wantDiags: 1,
+		},
+		{name: "empty file", csv: "", wantErr: true},
+		{name: "no race column", csv: "Lane,School\n1,Alpha\n", wantErr: true},
+		{name: "invalid race number", csv: "Race,Lane,School\none,1,Alpha\n", wantErr: true},
+		{name: "lane off the course", csv: "Race,Lane,School\n1,11,Alpha\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ReadCSV(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make([]RaceKey, len(data.Races))
+			for i, race := range data.Races {
+				got[i] = race.Key()
+			}
+			if len(got) != len(tt.wantRaces) {
+				t.Fatalf("races = %v, want %v", got, tt.wantRaces)
+			}
+			for i := range got {
+				if got[i] != tt.wantRaces[i] {
+					t.Errorf("races = %v, want %v", got, tt.wantRaces)
+					break
+				}
+			}
+			if data.LaneCount != tt.wantLanes {
+				t.Errorf("LaneCount = %d, want %d", data.LaneCount, tt.wantLanes)
+			}
+			if len(data.Diagnostics) != tt.wantDiags {
+				t.Errorf("diagnostics = %v, want %d", data.Diagnostics, tt.wantDiags)
+			}
+		})
+	}
+}
+
+func TestReadCSVEntries(t *testing.T) {
+	data, err := ReadCSV(strings.NewReader(
+		"Regatta,Date,Race,Lane,School,Info,Class,Flight,Place,Split,Time\n" +
+			"Spring Sprints,\"April 5, 2025\",1,2,Alpha,A,M1x,Final,1,,07:01.2\n" +
+			",,1,3,Bravo,B,,,2,,07:05.0\n"))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if data.RegattaName != "Spring Sprints" || data.DisplayDate() != "April 5, 2025" {
+		t.Errorf("regatta = %q on %q, want Spring Sprints on April 5, 2025", data.RegattaName, data.DisplayDate())
+	}
+	race := data.Races[0]
+	if race.BoatClass() != "M1x" || race.Flight() != "Final" {
+		t.Errorf("class and flight = %q, %q, want M1x, Final", race.BoatClass(), race.Flight())
+	}
+	want := RaceEntry{SchoolName: "Alpha", AdditionalInfo: "A", Place: "1", Time: "07:01.2"}
+	if got := race.Lanes[2]; got.SchoolName != want.SchoolName || got.AdditionalInfo != want.AdditionalInfo ||
+		got.Place != want.Place || got.Time != want.Time {
+		t.Errorf("lane 2 = %+v, want %+v", got, want)
+	}
+	if got := race.BoatCount(); got != 2 {
+		t.Errorf("BoatCount() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,104 @@
+package regattaClock
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// dateFormats are the date layouts accepted in draw titles and date cells
+var dateFormats = []string{
+	"January 2, 2006",
+	"January 2 2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"Monday, January 2, 2006",
+	"Mon, Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"2006-01-02",
+	"01/02/2006",
+	"1/2/2006",
+	"1/2/06",
+	"01-02-2006",
+	"01-02-06",
+	"02.01.2006",
+}
+
+// displayDateFormat is how parsed regatta dates are shown and printed
+const displayDateFormat = "January 2, 2006"
+
+// titleDateSeparator matches the run of spaces between the regatta name and
+// date in a combined title cell
+var titleDateSeparator = regexp.MustCompile(`\s{3,}`)
+
+// ordinalSuffix matches day suffixes such as the "th" in "13th"
+var ordinalSuffix = regexp.MustCompile(`(\d)(st|nd|rd|th)\b`)
+
+// ParseRegattaDate parses a regatta date in one of the common written formats
+// or as an Excel serial date number
+func ParseRegattaDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == emptyString {
+		return time.Time{}, fmt.Errorf("no date given")
+	}
+
+	// Excel stores dates as days since 1899-12-30
+	if serial, err := strconv.ParseFloat(value, 64); err == nil {
+		if serial < 1 || serial > 2958465 { // 9999-12-31
+			return time.Time{}, fmt.Errorf("invalid Excel serial date %q", value)
+		}
+		date, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid Excel serial date %q: %v", value, err)
+		}
+		return date, nil
+	}
+
+	// Drop ordinal suffixes such as "13th"
+	cleaned := ordinalSuffix.ReplaceAllString(value, "$1")
+	for _, format := range dateFormats {
+		if date, err := time.Parse(format, cleaned); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", value)
+}
+
+// splitTitle separates a combined title cell into the regatta name and the
+// date text that follows it after a run of spaces
+func splitTitle(value string) (string, string) {
+	parts := titleDateSeparator.Split(strings.TrimSpace(value), -1)
+	if len(parts) >= 2 {
+		return strings.TrimSpace(strings.Join(parts[:len(parts)-1], " ")), strings.TrimSpace(parts[len(parts)-1])
+	}
+	return strings.TrimSpace(value), emptyString
+}
+
+// setDate records the date text and its parsed value, reporting text that
+// cannot be parsed as a diagnostic at cell
+func (r *RegattaData) setDate(text string, cell string) {
+	r.DateText = strings.TrimSpace(text)
+	if r.DateText == emptyString {
+		return
+	}
+	date, err := ParseRegattaDate(r.DateText)
+	if err != nil {
+		r.addDiagnostic(SeverityWarning, cell, "could not parse regatta date: %v", err)
+		return
+	}
+	r.Date = date
+}
+
+// DisplayDate returns the regatta date for display, falling back to the text
+// from the draw when it could not be parsed
+func (r *RegattaData) DisplayDate() string {
+	if r.Date.IsZero() {
+		return r.DateText
+	}
+	return r.Date.Format(displayDateFormat)
+}
@@ -0,0 +1,68 @@
+package regattaClock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRegattaDate(t *testing.T) {
+	april5 := time.Date(2025, time.April, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "April 5, 2025", want: april5},
+		{value: "  April 5 2025  ", want: april5},
+		{value: "Apr 5, 2025", want: april5},
+		{value: "Saturday, April 5, 2025", want: april5},
+		{value: "April 5th, 2025", want: april5},
+		{value: "5 April 2025", want: april5},
+		{value: "2025-04-05", want: april5},
+		{value: "04/05/2025", want: april5},
+		{value: "4/5/25", want: april5},
+		{value: "05.04.2025", want: april5},
+		{value: "45752", want: april5},
+		{value: emptyString, wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "2958466", wantErr: true},
+		{value: "next Saturday", wantErr: true},
+		{value: "April 31, 2025", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRegattaDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRegattaDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseRegattaDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTitle(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantName string
+		wantDate string
+	}{
+		{value: "Spring Sprints     April 5, 2025", wantName: "Spring Sprints", wantDate: "April 5, 2025"},
+		{value: "  Spring Sprints   April 5, 2025  ", wantName: "Spring Sprints", wantDate: "April 5, 2025"},
+		{value: "Spring Sprints  April 5, 2025", wantName: "Spring Sprints  April 5, 2025"},
+		{value: "Spring   Sprints   April 5, 2025", wantName: "Spring Sprints", wantDate: "April 5, 2025"},
+		{value: "Spring Sprints", wantName: "Spring Sprints"},
+		{value: emptyString},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, date := splitTitle(tt.value)
+			if name != tt.wantName || date != tt.wantDate {
+				t.Errorf("splitTitle(%q) = %q, %q, want %q, %q", tt.value, name, date, tt.wantName, tt.wantDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package regattaClock
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity ranks how serious an import diagnostic is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "Info"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError:
+		return "Error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ImportDiagnostic is a problem found while importing a draw
+type ImportDiagnostic struct {
+	Severity Severity
+	Cell     string // Cell reference (or CSV line) the problem was found at, if any
+	Message  string
+}
+
+func (d ImportDiagnostic) String() string {
+	if d.Cell == emptyString {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", d.Severity, d.Cell, d.Message)
+}
+
+// addDiagnostic records a diagnostic against the regatta data
+func (r *RegattaData) addDiagnostic(severity Severity, cell string, format string, args ...any) {
+	r.Diagnostics = append(r.Diagnostics, ImportDiagnostic{
+		Severity: severity,
+		Cell:     cell,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// HasErrors reports whether any diagnostic is an error
+func (r *RegattaData) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRaceNumbers reports duplicate race numbers and gaps in the numbering
+// of each day or session. cellOf returns the location of a race for the report.
+func (r *RegattaData) checkRaceNumbers(cellOf func(RaceData) string) {
+	seen := make(map[RaceKey]RaceData)
+	numbers := make(map[string][]int)
+	for _, race := range r.Races {
+		if first, exists := seen[race.Key()]; exists {
+			r.addDiagnostic(SeverityError, cellOf(race),
+				"duplicate %s (first seen at %s)", race.Key(), cellOf(first))
+			continue
+		}
+		seen[race.Key()] = race
+		numbers[race.Session] = append(numbers[race.Session], race.RaceNumber)
+	}
+
+	for _, session := range r.Sessions() {
+		races := "races"
+		if session != emptyString {
+			races = session + " races"
+		}
+		sessionNumbers := numbers[session]
+		sort.Ints(sessionNumbers)
+		for i := 1; i < len(sessionNumbers); i++ {
+			if gap := sessionNumbers[i] - sessionNumbers[i-1]; gap == 2 {
+				missing := RaceKey{Session: session, Number: sessionNumbers[i-1] + 1}
+				r.addDiagnostic(SeverityWarning, emptyString, "%s is missing", missing)
+			} else if gap > 2 {
+				r.addDiagnostic(SeverityWarning, emptyString, "%s %d to %d are missing",
+					races, sessionNumbers[i-1]+1, sessionNumbers[i]-1)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package regattaClock
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCheckRaceNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		races []RaceKey // Races in draw order
+		want  []string
+	}{
+		{
+			name:  "numbered in order",
+			races: []RaceKey{{Number: 1}, {Number: 2}, {Number: 3}},
+		},
+		{
+			name:  "duplicate",
+			races: []RaceKey{{Number: 1}, {Number: 2}, {Number: 2}},
+			want:  []string{"Error row 3: duplicate race 2 (first seen at row 2)"},
+		},
+		{
+			name:  "one missing",
+			races: []RaceKey{{Number: 1}, {Number: 3}},
+			want:  []string{"Warning: race 2 is missing"},
+		},
+		{
+			name:  "several missing",
+			races: []RaceKey{{Number: 1}, {Number: 5}},
+			want:  []string{"Warning: races 2 to 4 are missing"},
+		},
+		{
+			name:  "out of order",
+			races: []RaceKey{{Number: 3}, {Number: 1}, {Number: 2}},
+		},
+		{
+			name:  "each session numbered from 1",
+			races: []RaceKey{{"Saturday", 1}, {"Saturday", 2}, {"Sunday", 1}, {"Sunday", 2}},
+		},
+		{
+			name:  "gaps reported by session",
+			races: []RaceKey{{"Saturday", 1}, {"Saturday", 3}, {"Sunday", 1}, {"Sunday", 4}},
+			want: []string{
+				"Warning: Saturday race 2 is missing",
+				"Warning: Sunday races 2 to 3 are missing",
+			},
+		},
+		{
+			name:  "duplicate within a session",
+			races: []RaceKey{{"Saturday", 1}, {"Sunday", 1}, {"Sunday", 1}},
+			want:  []string{"Error row 3: duplicate Sunday race 1 (first seen at row 2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &RegattaData{}
+			for i, key := range tt.races {
+				data.Races = append(data.Races, RaceData{Session: key.Session, RaceNumber: key.Number, StartRow: i + 1})
+			}
+			data.checkRaceNumbers(func(race RaceData) string {
+				return fmt.Sprintf("row %d", race.StartRow)
+			})
+
+			if len(data.Diagnostics) != len(tt.want) {
+				t.Fatalf("diagnostics = %v, want %v", data.Diagnostics, tt.want)
+			}
+			for i, d := range data.Diagnostics {
+				if got := d.String(); got != tt.want[i] {
+					t.Errorf("diagnostic %d = %q, want %q", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		severities []Severity
+		want       bool
+	}{
+		{name: "none"},
+		{name: "warnings only", severities: []Severity{SeverityInfo, SeverityWarning}},
+		{name: "error", severities: []Severity{SeverityWarning, SeverityError}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &RegattaData{}
+			for _, severity := range tt.severities {
+				data.addDiagnostic(severity, emptyString, "problem")
+			}
+			if got := data.HasErrors(); got != tt.want {
+				t.Errorf("HasErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
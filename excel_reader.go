@@ -5,7 +5,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/comagnaw/regattaClock/timing"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -16,26 +18,110 @@ type RaceEntry struct {
 	Place          string
 	Split          string
 	Time           string
+	Splits         []SplitTime // Time at each split station the lane was timed at, nearest the start first
+	Bow            int         // Bow number for head races, zero if the draw has none
+}
+
+// SplitTime is a lane's time at a split station
+type SplitTime struct {
+	Distance int    `json:"distance"`          // Metres from the start
+	Time     string `json:"time"`              // Time from the start
+	Segment  string `json:"segment,omitempty"` // Time since the lane's previous station, empty at the first
 }
 
 // RaceData represents the data for a single race
 type RaceData struct {
 	RaceNumber int
-	Lanes      map[int]RaceEntry // Lane number (1-6) to RaceEntry
-	RawData    [][]string        // Raw cell data from columns C through I for each row
+	Sheet      string            // Worksheet the race was read from
+	Session    string            // Day or session the race belongs to
+	StartRow   int               // First worksheet row of the race's block
+	LaneCount  int               // Number of lanes on the course for this race
+	Lanes      map[int]RaceEntry // Lane number (1-LaneCount) to RaceEntry
+	RawData    [][]string        // Raw cell data from the event column and each lane column for each row
 	Saved      bool              // Whether the race data has been saved
 	Approved   bool              // Whether the race data has been approved
+	Approvals  []Approval        // Referee approvals, oldest first
+	Protests   []Protest         // Protests and corrections that reopened the race, oldest first
+}
+
+// RaceKey identifies a race in a regatta. Multi-day workbooks may number the
+// races of each day or session from 1 again, so the session is part of it.
+type RaceKey struct {
+	Session string
+	Number  int
+}
+
+// String describes the race in messages, such as "race 3" or "Sunday race 3"
+func (k RaceKey) String() string {
+	if k.Session == emptyString {
+		return fmt.Sprintf("race %d", k.Number)
+	}
+	return fmt.Sprintf("%s race %d", k.Session, k.Number)
+}
+
+// Label describes the race in headings and window titles, such as "Race 3" or
+// "Sunday Race 3"
+func (k RaceKey) Label() string {
+	if k.Session == emptyString {
+		return fmt.Sprintf("Race %d", k.Number)
+	}
+	return fmt.Sprintf("%s Race %d", k.Session, k.Number)
+}
+
+// Key returns the key identifying the race in its regatta
+func (r RaceData) Key() RaceKey {
+	return RaceKey{Session: r.Session, Number: r.RaceNumber}
 }
 
 // RegattaData represents the structure of the regatta data we'll read from Excel
 type RegattaData struct {
 	RegattaName string
-	Date        string
+	Date        time.Time       // Regatta date, zero if it could not be parsed
+	DateText    string          // Date as written in the draw
+	FilePath    string          // Workbook the data was read from
+	Sheets      []string        // Worksheets the races were read from, in order
+	Layout      *WorkbookLayout // Layout the workbook was read with
+	LaneCount   int             // Number of lanes on the course
+	Timing      timing.Rules    // Precision and rounding for published times
+	Splits      []int           // Metres from the start of each split station
 	Races       []RaceData
+	Diagnostics []ImportDiagnostic // Problems found while importing
 }
 
-// ReadExcelFile reads an Excel file and returns the regatta data
+// ReadExcelFile reads an Excel file with the standard layout and returns the
+// regatta data
 func ReadExcelFile(filePath string) (*RegattaData, error) {
+	return ReadExcelFileWithLayout(filePath, DefaultLayout())
+}
+
+// ReadExcelFileWithLayout reads every sheet of an Excel file using the given
+// layout and returns the regatta data
+func ReadExcelFileWithLayout(filePath string, layout *WorkbookLayout) (*RegattaData, error) {
+	return ReadExcelSheets(filePath, layout, nil)
+}
+
+// ExcelSheetNames returns the names of the worksheets in an Excel file
+func ExcelSheetNames(filePath string) ([]string, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+	return f.GetSheetList(), nil
+}
+
+// ReadExcelSheets reads the named sheets of an Excel file, or every sheet if
+// sheets is empty, using the given layout and returns the regatta data. Each
+// sheet is a day or session of the regatta; the title and date are read from
+// the first sheet that has them.
+func ReadExcelSheets(filePath string, layout *WorkbookLayout, sheets []string) (*RegattaData, error) {
+	if layout == nil {
+		layout = DefaultLayout()
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Open the Excel file
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
@@ -43,155 +129,307 @@ func ReadExcelFile(filePath string) (*RegattaData, error) {
 	}
 	defer f.Close()
 
-	// Get the first sheet name
-	sheetName := f.GetSheetName(0)
-	if sheetName == emptyString {
+	// Default to every sheet in workbook order
+	if len(sheets) == 0 {
+		sheets = f.GetSheetList()
+	}
+	if len(sheets) == 0 {
 		return nil, fmt.Errorf("no sheets found in Excel file")
 	}
+	for _, sheetName := range sheets {
+		if index, err := f.GetSheetIndex(sheetName); err != nil || index < 0 {
+			return nil, fmt.Errorf("sheet %q not found in Excel file", sheetName)
+		}
+	}
+
+	// Create RegattaData
+	data := &RegattaData{
+		FilePath:  filePath,
+		Sheets:    sheets,
+		Layout:    layout,
+		LaneCount: layout.Lanes,
+		Timing:    timing.DefaultRules(),
+		Races:     make([]RaceData, 0),
+	}
+
+	for _, sheetName := range sheets {
+		if err := data.readSheet(f, sheetName, layout, len(sheets) > 1); err != nil {
+			return nil, err
+		}
+	}
+
+	data.checkRaceNumbers(func(race RaceData) string {
+		cell := cellName(layout.RaceColumn, race.StartRow)
+		if len(sheets) > 1 {
+			return race.Sheet + "!" + cell
+		}
+		return cell
+	})
+
+	sortRaces(data.Races)
+
+	return data, nil
+}
+
+// BoatClass returns the boat class text from the race's first row in column C
+func (r RaceData) BoatClass() string {
+	if len(r.RawData) > 0 && len(r.RawData[0]) > 0 {
+		return r.RawData[0][0]
+	}
+	return emptyString
+}
 
+// Flight returns the flight/heat/final text from the race's second row in column C
+func (r RaceData) Flight() string {
+	if len(r.RawData) > 1 && len(r.RawData[1]) > 0 {
+		return r.RawData[1][0]
+	}
+	return emptyString
+}
+
+// readSheet reads the title and races of one worksheet into the regatta data.
+// When qualify is set, diagnostic cells are prefixed with the sheet name.
+func (data *RegattaData) readSheet(f *excelize.File, sheetName string, layout *WorkbookLayout, qualify bool) error {
 	// Get merged cells
 	mergedCells, err := f.GetMergeCells(sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get merged cells: %v", err)
+		return fmt.Errorf("failed to get merged cells on sheet %s: %v", sheetName, err)
 	}
 
-	// Create RegattaData
-	data := &RegattaData{
-		Races: make([]RaceData, 0),
+	// ref qualifies a cell reference with the sheet name when reading several sheets
+	ref := func(cell string) string {
+		if qualify {
+			return sheetName + "!" + cell
+		}
+		return cell
 	}
 
-	// Find the title merged cell (A1:I2)
-	for _, mc := range mergedCells {
-		if mc.GetStartAxis() == "A1" && mc.GetEndAxis() == "I2" {
-			// Found our title cell
-			value := mc.GetCellValue()
+	// Find the title merged cell (A1:I2 in the standard layout)
+	if layout.TitleRange != emptyString && data.RegattaName == emptyString {
+		titleStart, titleEnd, _ := layout.titleCells()
+		titleFound := false
+		value := emptyString
+		for _, mc := range mergedCells {
+			if mc.GetStartAxis() == titleStart && mc.GetEndAxis() == titleEnd {
+				// Found our title cell
+				titleFound = true
+				value = mc.GetCellValue()
+				break
+			}
+		}
+		if !titleFound && titleStart == titleEnd {
+			// An unmerged title cell
+			value, _ = f.GetCellValue(sheetName, titleStart)
+			titleFound = value != emptyString
+		}
+
+		if layout.DateCell != emptyString {
+			// The title and date live in separate cells
+			data.RegattaName = strings.TrimSpace(value)
+			data.readDateCell(f, sheetName, layout.DateCell, ref(layout.DateCell))
+		} else {
 			// Split the value into title and date
-			parts := strings.Split(value, "                                                                                                                                                                                                                                                                   ")
-			if len(parts) >= 2 {
-				data.RegattaName = strings.TrimSpace(parts[0])
-				data.Date = strings.TrimSpace(parts[1])
-			} else {
-				data.RegattaName = strings.TrimSpace(value)
+			name, dateText := splitTitle(value)
+			data.RegattaName = name
+			data.setDate(dateText, ref(titleStart))
+		}
+
+		if !titleFound {
+			data.addDiagnostic(SeverityWarning, ref(layout.TitleRange), "no title cell found")
+		} else if data.RegattaName == emptyString {
+			data.addDiagnostic(SeverityWarning, ref(titleStart), "title cell is empty")
+		}
+		if titleFound && data.DateText == emptyString {
+			dateCell := layout.DateCell
+			if dateCell == emptyString {
+				dateCell = titleStart
 			}
-			break
+			data.addDiagnostic(SeverityWarning, ref(dateCell), "could not find a regatta date")
+		}
+	}
+
+	racesBefore := len(data.Races)
+
+	// getCellValue reads a cell, recording any read error as a diagnostic
+	getCellValue := func(cell string) string {
+		value, err := f.GetCellValue(sheetName, cell)
+		if err != nil {
+			data.addDiagnostic(SeverityError, ref(cell), "failed to read cell: %v", err)
 		}
+		return value
 	}
 
-	// Find race number cells (5-row merged cells in column A)
+	// Find race number cells (merged race blocks in the race column)
 	for _, mc := range mergedCells {
-		start := mc.GetStartAxis()
-		end := mc.GetEndAxis()
-
-		// Check if it's a 5-row merged cell in column A
-		if strings.HasPrefix(start, "A") && strings.HasPrefix(end, "A") {
-			startRow := getRowNumber(start)
-			endRow := getRowNumber(end)
-
-			if endRow-startRow == 4 { // 5 rows (inclusive)
-				// Get the race number
-				value := mc.GetCellValue()
-				raceNum, err := strconv.Atoi(value)
-				if err == nil {
-					// Create a new race with lanes
-					race := RaceData{
-						RaceNumber: raceNum,
-						Lanes:      make(map[int]RaceEntry),
-					}
+		startCol, startRow, err := excelize.SplitCellName(mc.GetStartAxis())
+		if err != nil {
+			continue
+		}
+		endCol, endRow, err := excelize.SplitCellName(mc.GetEndAxis())
+		if err != nil {
+			continue
+		}
 
-					// Initialize RawData for this race if not already done
-					if race.RawData == nil {
-						race.RawData = make([][]string, endRow-startRow+1)
-						for i := range race.RawData {
-							race.RawData[i] = make([]string, 7) // Columns C through I
-						}
-					}
+		// Check if it's a race block in the race column
+		if startCol == layout.RaceColumn && endCol == layout.RaceColumn {
+			// Get the race number
+			value := strings.TrimSpace(mc.GetCellValue())
+			if endRow-startRow != layout.RaceRows-1 { // Inclusive row count
+				data.addDiagnostic(SeverityWarning, ref(mc.GetStartAxis()+":"+mc.GetEndAxis()),
+					"merged block %q is %d rows, expected %d; skipped", value, endRow-startRow+1, layout.RaceRows)
+				continue
+			}
 
-					// Process each lane (columns D through I)
-					for lane := 1; lane <= 6; lane++ {
-						col := rune('A' + lane + 2) // D=1, E=2, F=3, G=4, H=5, I=6
-						entry := RaceEntry{}
-
-						// Get data for each row in the lane
-						for row := startRow; row <= endRow; row++ {
-							// Get the cell value
-							cellValue, _ := f.GetCellValue(sheetName, fmt.Sprintf("%c%d", col, row))
-
-							// Store raw data (columns C through I)
-							for rawCol := 0; rawCol < 7; rawCol++ {
-								rawCellValue, _ := f.GetCellValue(sheetName, fmt.Sprintf("%c%d", rune('C'+rawCol), row))
-								race.RawData[row-startRow][rawCol] = rawCellValue
-							}
-
-							// Assign data based on row position
-							switch row - startRow {
-							case 0: // First row - School Name
-								entry.SchoolName = strings.TrimSpace(cellValue)
-							case 1: // Second row - Additional Info
-								entry.AdditionalInfo = strings.TrimSpace(cellValue)
-							case 2: // Third row - Place
-								entry.Place = strings.TrimSpace(cellValue)
-							case 3: // Fourth row - Split
-								entry.Split = strings.TrimSpace(cellValue)
-							case 4: // Fifth row - Time
-								entry.Time = strings.TrimSpace(cellValue)
-							}
-						}
-
-						// Only add the lane if it has a school name or additional info
-						if entry.SchoolName != emptyString || entry.AdditionalInfo != emptyString {
-							race.Lanes[lane] = entry
-						}
-					}
+			raceNum, err := strconv.Atoi(value)
+			if err != nil {
+				data.addDiagnostic(SeverityWarning, ref(mc.GetStartAxis()),
+					"race number %q is not an integer; block skipped", value)
+				continue
+			}
+
+			// Create a new race with lanes
+			race := RaceData{
+				RaceNumber: raceNum,
+				Sheet:      sheetName,
+				Session:    sheetName,
+				StartRow:   startRow,
+				LaneCount:  layout.Lanes,
+				Lanes:      make(map[int]RaceEntry),
+			}
+
+			// Store raw data (event column, then each lane column)
+			race.RawData = make([][]string, layout.RaceRows)
+			for row := startRow; row <= endRow; row++ {
+				rawRow := make([]string, layout.Lanes+1)
+				rawRow[0] = getCellValue(cellName(layout.EventColumn, row))
+				for lane := 1; lane <= layout.Lanes; lane++ {
+					rawRow[lane] = getCellValue(cellName(layout.laneColumn(lane), row))
+				}
+				race.RawData[row-startRow] = rawRow
+			}
+
+			// Process each lane column
+			for lane := 1; lane <= layout.Lanes; lane++ {
+				entry := RaceEntry{
+					SchoolName:     strings.TrimSpace(race.RawData[layout.SchoolRow][lane]),
+					AdditionalInfo: strings.TrimSpace(race.RawData[layout.InfoRow][lane]),
+					Place:          strings.TrimSpace(race.RawData[layout.PlaceRow][lane]),
+					Split:          strings.TrimSpace(race.RawData[layout.SplitRow][lane]),
+					Time:           strings.TrimSpace(race.RawData[layout.TimeRow][lane]),
+				}
+				schoolCell := ref(cellName(layout.laneColumn(lane), startRow+layout.SchoolRow))
 
-					// Add the race to our data
-					data.Races = append(data.Races, race)
+				// Only add the lane if it has a school name or additional info
+				if entry.SchoolName != emptyString || entry.AdditionalInfo != emptyString {
+					race.Lanes[lane] = entry
+					// The event may be given for the whole race in the event column
+					if entry.SchoolName != emptyString && entry.AdditionalInfo == emptyString &&
+						race.BoatClass() == emptyString && race.Flight() == emptyString {
+						data.addDiagnostic(SeverityWarning, schoolCell,
+							"race %d lane %d has a school but no event", raceNum, lane)
+					}
+				} else if entry.Place != emptyString || entry.Split != emptyString || entry.Time != emptyString {
+					data.addDiagnostic(SeverityWarning, schoolCell,
+						"race %d lane %d has results but no school or event; lane skipped", raceNum, lane)
 				}
 			}
+
+			// Add the race to our data
+			data.Races = append(data.Races, race)
 		}
 	}
 
-	// Sort races by race number
-	sort.Slice(data.Races, func(i, j int) bool {
-		return data.Races[i].RaceNumber < data.Races[j].RaceNumber
-	})
+	if qualify && len(data.Races) == racesBefore {
+		data.addDiagnostic(SeverityInfo, sheetName, "no races found on sheet")
+	}
 
-	// Print the races in order
-	fmt.Println("\nRaces in sequential order:")
-	for _, race := range data.Races {
-		fmt.Printf("\nRace %d:\n", race.RaceNumber)
-		// Print lanes in order
-		for lane := 1; lane <= 6; lane++ {
-			if entry, exists := race.Lanes[lane]; exists {
-				fmt.Printf("  Lane %d:\n", lane)
-				fmt.Printf("    School: %s\n", entry.SchoolName)
-				fmt.Printf("    Additional Info: %s\n", entry.AdditionalInfo)
-				fmt.Printf("    Place: %s\n", entry.Place)
-				fmt.Printf("    Split: %s\n", entry.Split)
-				fmt.Printf("    Time: %s\n", entry.Time)
-			}
+	return nil
+}
+
+// Sessions returns the days or sessions of the regatta in the order their
+// first race appears
+func (r *RegattaData) Sessions() []string {
+	sessions := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, race := range r.Races {
+		if !seen[race.Session] {
+			seen[race.Session] = true
+			sessions = append(sessions, race.Session)
+		}
+	}
+	return sessions
+}
+
+// sortRaces orders races by day or session, in the order each first appears,
+// and then by race number
+func sortRaces(races []RaceData) {
+	sessions := make(map[string]int)
+	for _, race := range races {
+		if _, seen := sessions[race.Session]; !seen {
+			sessions[race.Session] = len(sessions)
+		}
+	}
+	sort.SliceStable(races, func(i, j int) bool {
+		if si, sj := sessions[races[i].Session], sessions[races[j].Session]; si != sj {
+			return si < sj
 		}
+		return races[i].RaceNumber < races[j].RaceNumber
+	})
+}
 
-		// Print RawData
-		fmt.Println("\n  Raw Data (Columns C through I):")
-		for row := 0; row < len(race.RawData); row++ {
-			fmt.Printf("    Row %d: ", row+1)
-			for col := 0; col < len(race.RawData[row]); col++ {
-				fmt.Printf("[%s] ", race.RawData[row][col])
+// readDateCell reads the regatta date from its own cell, accepting either a
+// formatted date or an Excel serial date. ref locates the cell in diagnostics.
+func (r *RegattaData) readDateCell(f *excelize.File, sheetName string, cell string, ref string) {
+	value, err := f.GetCellValue(sheetName, cell)
+	if err != nil {
+		r.addDiagnostic(SeverityError, ref, "failed to read cell: %v", err)
+		return
+	}
+	if _, err := ParseRegattaDate(value); err != nil {
+		// Fall back to the stored value, which is the serial number for date cells
+		if raw, err := f.GetCellValue(sheetName, cell, excelize.Options{RawCellValue: true}); err == nil && raw != emptyString {
+			if _, err := ParseRegattaDate(raw); err == nil {
+				value = raw
 			}
-			fmt.Println()
 		}
 	}
+	r.setDate(value, ref)
+}
 
-	return data, nil
+// NumLanes returns the number of lanes on the course for the race, falling
+// back to the standard course or the highest lane entered
+func (r RaceData) NumLanes() int {
+	laneCount := r.LaneCount
+	if laneCount == 0 {
+		laneCount = defaultLaneCount
+	}
+	for lane := range r.Lanes {
+		if lane > laneCount {
+			laneCount = lane
+		}
+	}
+	return laneCount
 }
 
-// Helper function to extract row number from cell reference
-func getRowNumber(cellRef string) int {
-	row := 0
-	for _, c := range cellRef {
-		if c >= '0' && c <= '9' {
-			row = row*10 + int(c-'0')
+// BoatCount returns the number of lanes with a school entered
+func (r RaceData) BoatCount() int {
+	boatCount := 0
+	for _, entry := range r.Lanes {
+		if entry.SchoolName != emptyString {
+			boatCount++
 		}
 	}
-	return row
+	return boatCount
+}
+
+// Title returns the race heading, e.g. "Race 3 (5 Boats) - M-1x - Heat 1"
+func (r RaceData) Title() string {
+	title := fmt.Sprintf("Race %d (%d Boats)", r.RaceNumber, r.BoatCount())
+	if boatClass := r.BoatClass(); boatClass != emptyString {
+		title = fmt.Sprintf("%s - %s", title, boatClass)
+	}
+	if flight := r.Flight(); flight != emptyString {
+		title = fmt.Sprintf("%s - %s", title, flight)
+	}
+	return title
 }
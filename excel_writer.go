@@ -0,0 +1,75 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// WriteRaceResults writes the Place, Split and Time of each lane of a race back
+// into the race's block in the workbook the regatta was read from, using the
+// layout it was read with (rows 3-5 in the standard layout). The time and
+// segment at each split station go in the layout's split rows, if it has any.
+// Only cell values are written, so the sheet's merges and formatting are kept.
+func WriteRaceResults(data *RegattaData, race RaceData) error {
+	if data == nil || data.FilePath == emptyString {
+		return fmt.Errorf("no source workbook to save to")
+	}
+	if race.StartRow < 1 {
+		return fmt.Errorf("%s has no position in the workbook", race.Key())
+	}
+
+	// Open the source workbook
+	f, err := excelize.OpenFile(data.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+
+	layout := data.Layout
+	if layout == nil {
+		layout = DefaultLayout()
+	}
+
+	// Write to the sheet the race was read from
+	sheetName := race.Sheet
+	if sheetName == emptyString {
+		sheetName = f.GetSheetName(0)
+	}
+
+	// Write each lane column
+	for lane := 1; lane <= layout.Lanes; lane++ {
+		entry, exists := race.Lanes[lane]
+		if !exists {
+			continue
+		}
+		col := layout.laneColumn(lane)
+
+		values := map[int]string{
+			layout.PlaceRow: entry.Place,
+			layout.SplitRow: entry.Split,
+			layout.TimeRow:  entry.Time,
+		}
+		for station, row := range layout.SplitRows {
+			values[row] = emptyString
+			if station < len(data.Splits) {
+				if split, ok := splitAt(entry.Splits, data.Splits[station]); ok {
+					values[row] = split.Text()
+				}
+			}
+		}
+		for offset, value := range values {
+			cell := cellName(col, race.StartRow+offset)
+			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+				return fmt.Errorf("failed to write cell %s: %v", cell, err)
+			}
+		}
+	}
+
+	// Save the workbook in place
+	if err := f.Save(); err != nil {
+		return fmt.Errorf("failed to save Excel file: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+func (a *App) exportResults() {
+	if a.regattaData == nil {
+		dialog.ShowInformation("Export", "Import a regatta table before exporting results.", a.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if writer == nil {
+			// User cancelled
+			return
+		}
+		defer writer.Close()
+
+		// Pick the format from the file extension
+		format, err := ParseExportFormat(writer.URI().Extension())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("only .csv and .json exports are supported"), a.window)
+			return
+		}
+
+		if err := ExportResults(writer, a.regattaData, format); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+
+		dialog.ShowInformation("Export", "Successfully exported results", a.window)
+	}, a.window)
+	saveDialog.SetFileName("results.csv")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+	saveDialog.Show()
+}
+
+func (a *App) exportRacePDF(race RaceData) {
+	if a.regattaData == nil {
+		return
+	}
+
+	// Use the approved results stored in the regatta data
+	for _, stored := range a.regattaData.Races {
+		if stored.Key() == race.Key() {
+			a.savePDF(fmt.Sprintf("%s-results.pdf", raceFileName(stored)), func(w fyne.URIWriteCloser) error {
+				return WriteRacePDF(w, a.regattaData, stored)
+			})
+			return
+		}
+	}
+
+	dialog.ShowError(fmt.Errorf("%s not found in regatta data", race.Key()), a.window)
+}
+
+func (a *App) exportRegattaPDF() {
+	if a.regattaData == nil {
+		dialog.ShowInformation("Results Booklet", "Import a regatta table before printing results.", a.window)
+		return
+	}
+
+	a.savePDF("regatta-results.pdf", func(w fyne.URIWriteCloser) error {
+		return WriteRegattaPDF(w, a.regattaData)
+	})
+}
+
+// savePDF asks for a destination file and writes a PDF to it with write
+func (a *App) savePDF(fileName string, write func(fyne.URIWriteCloser) error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if writer == nil {
+			// User cancelled
+			return
+		}
+		defer writer.Close()
+
+		if err := write(writer); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+
+		dialog.ShowInformation("Results PDF", "Successfully wrote results PDF", a.window)
+	}, a.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
+	saveDialog.Show()
+}
@@ -4,6 +4,7 @@ go 1.23.5
 
 require (
 	fyne.io/fyne/v2 v2.6.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/xuri/excelize/v2 v2.9.0
 )
 
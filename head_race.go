@@ -0,0 +1,560 @@
+package regattaClock
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// headRaceHeaders are the column headings of the head race results table
+var headRaceHeaders = []string{"Event", "Place", "Bow", "Crew", "Start", "Finish", "Time"}
+
+// headRaceEntry is where a crew sits in the draw
+type headRaceEntry struct {
+	Race RaceKey `json:"race"`
+	Lane int     `json:"lane"`
+}
+
+// headRaceMeta is recorded with the open event of a head race session: the
+// session of the draw it times and the draw lane of each crew, in the order
+// the crews were added
+type headRaceMeta struct {
+	Session string          `json:"session"`
+	Entries []headRaceEntry `json:"entries"`
+}
+
+// headRaceWindow is a window timing a head race, where crews start at
+// intervals and are matched at the finish by bow number
+type headRaceWindow struct {
+	app           *App
+	window        fyne.Window
+	sessionName   string
+	session       *timing.HeadRaceSession
+	results       timing.HeadResults
+	entries       []headRaceEntry  // Draw lane of each crew, in the order they were added
+	log           *timing.EventLog // Session events
+	journal       *timing.Journal  // Nil if the session is not journaled
+	journalFailed bool             // Whether a journal write error has been shown
+	clock         *canvas.Text
+	startList     *widget.List
+	finishList    *widget.List
+	resultsTable  *widget.Table
+	stopChan      chan struct{}
+}
+
+// openHeadRace asks which day or session to time as a head race when there
+// is more than one, then opens its window
+func (a *App) openHeadRace() {
+	if a.regattaData == nil {
+		dialog.ShowError(fmt.Errorf("load a regatta before timing a head race"), a.window)
+		return
+	}
+
+	sessions := a.regattaData.Sessions()
+	if len(sessions) <= 1 {
+		session := emptyString
+		if len(sessions) == 1 {
+			session = sessions[0]
+		}
+		a.openHeadRaceSession(session)
+		return
+	}
+
+	sessionSelect := widget.NewSelect(sessions, nil)
+	sessionSelect.SetSelected(sessions[0])
+	dialog.ShowForm(
+		"Time Head Race",
+		"Open",
+		"Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Session", sessionSelect)},
+		func(confirmed bool) {
+			if confirmed {
+				a.openHeadRaceSession(sessionSelect.Selected)
+			}
+		},
+		a.window,
+	)
+}
+
+// openHeadRaceSession opens a head race window for the races in a session.
+// Each race is an event, and crews are sent off the start in race and lane
+// order. Crews keep their bow numbers from the draw; those without one are
+// numbered on from the highest, and any can be renumbered in the window.
+func (a *App) openHeadRaceSession(sessionName string) {
+	if h, open := a.headRaceWindows[sessionName]; open {
+		h.window.RequestFocus()
+		return
+	}
+	// Carry on with the head race's last session, such as one opened from a project
+	if log, timed := a.headRaceLogs[sessionName]; timed {
+		if err := a.resumeHeadRace(log.Events()); err != nil {
+			dialog.ShowError(err, a.window)
+		}
+		return
+	}
+
+	races := make([]RaceData, 0)
+	for _, race := range a.regattaData.Races {
+		if race.Session == sessionName {
+			races = append(races, race)
+		}
+	}
+	sortRaces(races)
+
+	nextBow := 1
+	for _, race := range races {
+		for _, entry := range race.Lanes {
+			if entry.SchoolName != emptyString && entry.Bow >= nextBow {
+				nextBow = entry.Bow + 1
+			}
+		}
+	}
+	crews := make([]timing.Crew, 0)
+	meta := headRaceMeta{Session: sessionName}
+	bows := make(map[int]RaceKey)
+	for _, race := range races {
+		lanes := make([]int, 0, len(race.Lanes))
+		for lane, entry := range race.Lanes {
+			if entry.SchoolName != emptyString {
+				lanes = append(lanes, lane)
+			}
+		}
+		sort.Ints(lanes)
+
+		for _, lane := range lanes {
+			entry := race.Lanes[lane]
+			name := entry.SchoolName
+			if entry.AdditionalInfo != emptyString {
+				name = fmt.Sprintf("%s (%s)", name, entry.AdditionalInfo)
+			}
+			bow := entry.Bow
+			if bow == 0 {
+				bow = nextBow
+				nextBow++
+			}
+			if other, taken := bows[bow]; taken {
+				dialog.ShowError(fmt.Errorf("%s lane %d has bow %d, which %s already has", race.Key(), lane, bow, other), a.window)
+				return
+			}
+			bows[bow] = race.Key()
+			crews = append(crews, timing.Crew{Bow: bow, Event: race.Title(), Name: name})
+			meta.Entries = append(meta.Entries, headRaceEntry{Race: race.Key(), Lane: lane})
+		}
+	}
+	if len(crews) == 0 {
+		dialog.ShowError(fmt.Errorf("no crews to time in %q", sessionName), a.window)
+		return
+	}
+
+	// Journal the session from the start so a crash loses nothing
+	open, err := headOpenEvent(meta)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	journal, journalErr := a.startHeadJournal(sessionName)
+	log := timing.NewEventLog(nil, journalRecorder(journal))
+	log.Record(open)
+	session := timing.NewHeadRaceSession(a.timeSource)
+	session.SetRecorder(log)
+	session.SetRules(a.timingRules())
+	for _, crew := range crews {
+		if err := session.AddCrew(crew); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+	}
+
+	h := a.showHeadRace(meta, session, log, journal)
+	if journalErr != nil {
+		h.warnNotJournaled(journalErr)
+	}
+}
+
+// showHeadRace opens a head race window timing session, which records its
+// changes to log and, if it is not nil, journal
+func (a *App) showHeadRace(meta headRaceMeta, session *timing.HeadRaceSession, log *timing.EventLog, journal *timing.Journal) *headRaceWindow {
+	h := &headRaceWindow{
+		app:         a,
+		window:      a.app.NewWindow(fmt.Sprintf("Head Race %s", meta.Session)),
+		sessionName: meta.Session,
+		session:     session,
+		entries:     meta.Entries,
+		log:         log,
+		journal:     journal,
+		stopChan:    make(chan struct{}),
+	}
+	h.results = h.session.Results()
+
+	h.window.SetContent(h.setupContent())
+	h.window.Resize(fyne.NewSize(1240, 800))
+	h.window.Canvas().SetOnTypedKey(h.typedKey)
+
+	go h.startClockUpdate()
+	h.window.SetOnClosed(func() {
+		close(h.stopChan)
+		if err := h.closeJournal(); err != nil {
+			dialog.ShowError(err, a.window)
+		}
+		delete(a.headRaceWindows, meta.Session)
+	})
+	a.headRaceLogs[meta.Session] = log
+	a.headRaceWindows[meta.Session] = h
+	h.window.Show()
+	return h
+}
+
+func (h *headRaceWindow) setupContent() fyne.CanvasObject {
+	h.clock = canvas.NewText(h.session.Rules().Precision.Format(0), color.White)
+	h.clock.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
+	h.clock.Alignment = fyne.TextAlignCenter
+	h.clock.TextSize = 48
+
+	buttons := container.NewHBox(
+		layout.NewSpacer(),
+		widget.NewButton("Start Session (F2)", h.start),
+		layout.NewSpacer(),
+		widget.NewButton("Crew Start (F3)", h.captureStart),
+		layout.NewSpacer(),
+		widget.NewButton("Crew Finish (F4)", h.captureFinish),
+		layout.NewSpacer(),
+		widget.NewButton("Stop", h.stop),
+		layout.NewSpacer(),
+		widget.NewButton("Clear", h.clear),
+		layout.NewSpacer(),
+		widget.NewButton("Store Results", h.confirmStoreResults),
+		layout.NewSpacer(),
+	)
+
+	h.startList = h.captureList(func() []timing.HeadCapture { return h.results.Starts }, h.session.AssignStart)
+	h.finishList = h.captureList(func() []timing.HeadCapture { return h.results.Finishes }, h.session.AssignFinish)
+
+	startHeader := widget.NewLabel("Start Line")
+	startHeader.TextStyle = fyne.TextStyle{Bold: true}
+	finishHeader := widget.NewLabel("Finish Line")
+	finishHeader.TextStyle = fyne.TextStyle{Bold: true}
+
+	captures := container.NewGridWithColumns(2,
+		container.NewBorder(startHeader, nil, nil, nil, h.startList),
+		container.NewBorder(finishHeader, nil, nil, nil, h.finishList),
+	)
+
+	h.resultsTable = widget.NewTable(
+		func() (int, int) {
+			return len(h.results.Crews) + 1, len(headRaceHeaders)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel(emptyString)
+		},
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			cell.(*widget.Label).SetText(h.resultsCell(id.Row, id.Col))
+		},
+	)
+	h.resultsTable.OnSelected = func(id widget.TableCellID) {
+		h.resultsTable.Unselect(id)
+		if id.Row == 0 {
+			return
+		}
+		switch id.Col {
+		case 1: // Place column
+			h.editPlace(h.results.Crews[id.Row-1].Bow)
+		case 2: // Bow column
+			h.editBow(h.results.Crews[id.Row-1].Bow)
+		}
+	}
+	h.resultsTable.SetColumnWidth(0, 400) // Event
+	h.resultsTable.SetColumnWidth(3, 300) // Crew
+	for _, col := range []int{1, 2, 4, 5, 6} {
+		h.resultsTable.SetColumnWidth(col, 100)
+	}
+
+	return container.NewBorder(
+		container.NewVBox(container.NewCenter(h.clock), buttons),
+		nil, nil, nil,
+		container.NewVSplit(captures, h.resultsTable),
+	)
+}
+
+// captureList creates a scrolling list of start or finish captures, each
+// with an entry for the crew's bow number. A bow number is assigned when it
+// is submitted, so typing one digit at a time does not reassign other
+// captures on the way.
+func (h *headRaceWindow) captureList(captures func() []timing.HeadCapture, assign func(int, int) error) *widget.List {
+	return widget.NewList(
+		func() int {
+			return len(captures())
+		},
+		func() fyne.CanvasObject {
+			bowEntry := widget.NewEntry()
+			bowEntry.SetPlaceHolder("Bow")
+			return container.NewGridWithColumns(3, widget.NewLabel(emptyString), bowEntry, widget.NewLabel(emptyString))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			capture := captures()[id]
+			bowEntry := row.Objects[1].(*widget.Entry)
+
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%d", id+1))
+			row.Objects[2].(*widget.Label).SetText(h.results.Rules.Precision.Format(capture.Time))
+
+			// Rows are reused, so rebind the entry to this capture
+			if capture.Bow != 0 {
+				bowEntry.SetText(strconv.Itoa(capture.Bow))
+			} else {
+				bowEntry.SetText(emptyString)
+			}
+			bowEntry.OnSubmitted = func(text string) {
+				bow := 0
+				if strings.TrimSpace(text) != emptyString {
+					var err error
+					if bow, err = strconv.Atoi(strings.TrimSpace(text)); err != nil {
+						dialog.ShowError(fmt.Errorf("invalid bow number %q", text), h.window)
+						return
+					}
+				}
+				if err := assign(id, bow); err != nil {
+					dialog.ShowError(err, h.window)
+				}
+				// Assigning a bow may take it from another capture
+				h.refresh()
+			}
+		},
+	)
+}
+
+// resultsCell returns the text of a head race results table cell, with the
+// headings in row zero
+func (h *headRaceWindow) resultsCell(row int, col int) string {
+	if row == 0 {
+		return headRaceHeaders[col]
+	}
+	result := h.results.Crews[row-1]
+	precision := h.results.Rules.Precision
+	switch col {
+	case 0:
+		return result.Event
+	case 1:
+		return result.Place
+	case 2:
+		return strconv.Itoa(result.Bow)
+	case 3:
+		return result.Name
+	case 4:
+		if result.Started {
+			return precision.Format(result.Start)
+		}
+	case 5:
+		if result.Finished {
+			return precision.Format(result.Finish)
+		}
+	case 6:
+		if result.Timed {
+			return precision.Format(result.Elapsed)
+		}
+	}
+	return emptyString
+}
+
+// editPlace lets the user mark a crew DNS, DNF or DQ, or put it back into
+// the ranking with Next Place
+func (h *headRaceWindow) editPlace(bow int) {
+	options := []string{string(timing.PlaceDNS), string(timing.PlaceDNF), string(timing.PlaceDQ), nextPlace}
+	selectWidget := widget.NewSelect(options, func(value string) {
+		code := timing.PlaceNone
+		if value != nextPlace {
+			code = timing.PlaceCode(value)
+		}
+		if err := h.session.SetPlaceCode(bow, code); err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.refreshResults()
+	})
+	dialog.ShowCustom(fmt.Sprintf("Edit Place - Bow %d", bow), "Close", selectWidget, h.window)
+}
+
+// editBow lets the user renumber a crew whose bow number in the draw is wrong
+func (h *headRaceWindow) editBow(bow int) {
+	bowEntry := widget.NewEntry()
+	bowEntry.SetText(strconv.Itoa(bow))
+	dialog.ShowForm(
+		fmt.Sprintf("Renumber Bow %d", bow),
+		"Renumber",
+		"Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Bow", bowEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			newBow, err := strconv.Atoi(strings.TrimSpace(bowEntry.Text))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("invalid bow number %q", bowEntry.Text), h.window)
+				return
+			}
+			if err := h.session.SetBow(bow, newBow); err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.refresh()
+		},
+		h.window,
+	)
+}
+
+// refresh updates the capture lists and results from the session
+func (h *headRaceWindow) refresh() {
+	h.results = h.session.Results()
+	h.startList.Refresh()
+	h.finishList.Refresh()
+	h.resultsTable.Refresh()
+	h.checkJournal()
+}
+
+// refreshResults updates the results table without redrawing the capture
+// lists
+func (h *headRaceWindow) refreshResults() {
+	h.results = h.session.Results()
+	h.resultsTable.Refresh()
+	h.checkJournal()
+}
+
+func (h *headRaceWindow) typedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyF2:
+		h.start()
+	case fyne.KeyF3:
+		h.captureStart()
+	case fyne.KeyF4:
+		h.captureFinish()
+	}
+}
+
+func (h *headRaceWindow) start() {
+	if err := h.session.Start(); err != nil {
+		return
+	}
+	h.refresh()
+}
+
+func (h *headRaceWindow) captureStart() {
+	if _, err := h.session.CaptureStart(); err != nil {
+		return
+	}
+	h.refresh()
+	h.startList.ScrollToBottom()
+}
+
+func (h *headRaceWindow) captureFinish() {
+	if _, err := h.session.CaptureFinish(); err != nil {
+		return
+	}
+	h.refresh()
+	h.finishList.ScrollToBottom()
+}
+
+func (h *headRaceWindow) stop() {
+	if err := h.session.Stop(); err != nil {
+		return
+	}
+	h.refresh()
+}
+
+func (h *headRaceWindow) clear() {
+	dialog.ShowConfirm("Clear Head Race", "Discard every start and finish capture?", func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := h.session.Clear(); err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.clock.Text = h.session.Rules().Precision.Format(0)
+		h.clock.Refresh()
+		h.refresh()
+	}, h.window)
+}
+
+// confirmStoreResults asks before storing, since head race results go into
+// the draw without a referee's approval
+func (h *headRaceWindow) confirmStoreResults() {
+	crews := len(h.session.Results().Crews)
+	message := fmt.Sprintf("Store results for %d crews in the draw?\n\nHead race results are stored without referee approval.", crews)
+	dialog.ShowConfirm("Store Results", message, func(confirmed bool) {
+		if confirmed {
+			h.storeResults()
+		}
+	}, h.window)
+}
+
+// storeResults copies each crew's place and time into its lane of the draw,
+// so head race results are exported and printed like any other race
+func (h *headRaceWindow) storeResults() {
+	results := h.session.Results()
+	entries := make(map[int]headRaceEntry)
+	for i, crew := range h.session.Crews() {
+		if i < len(h.entries) {
+			entries[crew.Bow] = h.entries[i]
+		}
+	}
+	stored := 0
+	for _, result := range results.Crews {
+		entry, exists := entries[result.Bow]
+		if !exists {
+			continue
+		}
+		for i := range h.app.regattaData.Races {
+			race := &h.app.regattaData.Races[i]
+			if race.Key() != entry.Race {
+				continue
+			}
+			lane := race.Lanes[entry.Lane]
+			lane.Bow = result.Bow // Keep bows renumbered in the window
+			lane.Place = result.Place
+			lane.Split = emptyString
+			lane.Time = emptyString
+			lane.Splits = nil
+			if result.Timed {
+				lane.Time = results.Rules.Precision.Format(result.Elapsed)
+			}
+			race.Lanes[entry.Lane] = lane
+			stored++
+			break
+		}
+	}
+	dialog.ShowInformation("Store Results", fmt.Sprintf("Stored results for %d crews", stored), h.window)
+}
+
+func (h *headRaceWindow) startClockUpdate() {
+	interval := time.Duration(h.session.Rules().Precision.Unit())
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if h.session.State() == timing.StateRunning {
+				formatted := h.session.Rules().Precision.Format(h.session.Elapsed())
+				fyne.Do(func() {
+					h.clock.Text = formatted
+					h.clock.Refresh()
+				})
+			}
+		case <-h.stopChan:
+			return
+		}
+	}
+}
@@ -9,6 +9,6 @@ func (a *App) winningTimeInput() *widget.FormItem {
 		"Winning Time:",
 		a.winningTime,
 	)
-	item.HintText = zeroTime
+	item.HintText = a.zeroTime()
 	return item
 }
@@ -0,0 +1,461 @@
+package regattaClock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"fyne.io/fyne/v2/dialog"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// journalDir returns the directory race session journals are kept in,
+// creating it if needed
+func (a *App) journalDir() (string, error) {
+	dir := filepath.Join(a.app.Storage().RootURI().Path(), "journal")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return emptyString, fmt.Errorf("failed to create journal directory: %v", err)
+	}
+	return dir, nil
+}
+
+// startJournal creates the journal file for a race session. The race is
+// timed without a journal if it cannot be created.
+func (a *App) startJournal(race RaceData) (*timing.Journal, error) {
+	dir, err := a.journalDir()
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", raceFileName(race), time.Now().Format("20060102-150405.000"))
+	return timing.CreateJournal(filepath.Join(dir, name))
+}
+
+// startHeadJournal creates the journal file for a head race session
+func (a *App) startHeadJournal(sessionName string) (*timing.Journal, error) {
+	dir, err := a.journalDir()
+	if err != nil {
+		return nil, err
+	}
+	name := "head"
+	if session := fileNamePart(sessionName); session != emptyString {
+		name = fmt.Sprintf("head-%s", session)
+	}
+	name = fmt.Sprintf("%s-%s.jsonl", name, time.Now().Format("20060102-150405.000"))
+	return timing.CreateJournal(filepath.Join(dir, name))
+}
+
+// raceFileName names a race's files, such as "race-3" or "race-sunday-3"
+func raceFileName(race RaceData) string {
+	if session := fileNamePart(race.Session); session != emptyString {
+		return fmt.Sprintf("race-%s-%d", session, race.RaceNumber)
+	}
+	return fmt.Sprintf("race-%d", race.RaceNumber)
+}
+
+// fileNamePart keeps the letters and digits of a session name, so it is safe
+// in a file name
+func fileNamePart(name string) string {
+	part := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, name)
+	return strings.Trim(part, "-")
+}
+
+// warnNotJournaled tells the user the race window's session has no journal
+// because it could not be created
+func (a *App) warnNotJournaled(err error) {
+	a.journalFailed = true
+	dialog.ShowError(fmt.Errorf("this race is not being journaled and will not survive a crash: %v", err), a.window)
+}
+
+// journalRecorder returns journal as a recorder, or nil if there is no journal
+func journalRecorder(journal *timing.Journal) timing.Recorder {
+	if journal == nil {
+		return nil
+	}
+	return journal
+}
+
+// openEvent is the first event of a race session, recording the race it times
+func openEvent(race RaceData) (timing.Event, error) {
+	meta, err := json.Marshal(race)
+	if err != nil {
+		return timing.Event{}, fmt.Errorf("failed to record %s: %v", race.Key(), err)
+	}
+	return timing.Event{
+		Kind:      timing.EventOpen,
+		At:        time.Now(),
+		LaneCount: race.NumLanes(),
+		Meta:      meta,
+	}, nil
+}
+
+// headOpenEvent is the first event of a head race session, recording the
+// session of the draw it times and where each crew sits in it
+func headOpenEvent(meta headRaceMeta) (timing.Event, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return timing.Event{}, fmt.Errorf("failed to record head race %s: %v", meta.Session, err)
+	}
+	return timing.Event{Kind: timing.EventOpen, At: time.Now(), Head: true, Meta: data}, nil
+}
+
+// recordEvent records an application event, such as an approval, with the
+// window's session events
+func (a *App) recordEvent(event timing.Event) {
+	if a.raceLog == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = a.session.Now()
+	}
+	a.raceLog.Record(event)
+}
+
+// closeJournal marks the window's session as closed normally, so it is not
+// offered for recovery
+func (a *App) closeJournal() error {
+	a.recordEvent(timing.Event{Kind: timing.EventClosed})
+	if a.journal == nil {
+		return nil
+	}
+	err := a.journal.Close()
+	a.journal = nil
+	return err
+}
+
+// checkJournal warns once if the window's journal could not be written
+func (a *App) checkJournal() {
+	if a.journal == nil || a.journalFailed {
+		return
+	}
+	if err := a.journal.Err(); err != nil {
+		a.journalFailed = true
+		dialog.ShowError(fmt.Errorf("this race is no longer being journaled and will not survive a crash: %v", err), a.window)
+	}
+}
+
+// warnNotJournaled tells the user the head race has no journal because it
+// could not be created
+func (h *headRaceWindow) warnNotJournaled(err error) {
+	h.journalFailed = true
+	dialog.ShowError(fmt.Errorf("this head race is not being journaled and will not survive a crash: %v", err), h.window)
+}
+
+// closeJournal marks the head race session as closed normally, so it is not
+// offered for recovery
+func (h *headRaceWindow) closeJournal() error {
+	h.log.Record(timing.Event{Kind: timing.EventClosed, At: h.session.Now()})
+	if h.journal == nil {
+		return nil
+	}
+	err := h.journal.Close()
+	h.journal = nil
+	return err
+}
+
+// checkJournal warns once if the head race's journal could not be written
+func (h *headRaceWindow) checkJournal() {
+	if h.journal == nil || h.journalFailed {
+		return
+	}
+	if err := h.journal.Err(); err != nil {
+		h.journalFailed = true
+		dialog.ShowError(fmt.Errorf("this head race is no longer being journaled and will not survive a crash: %v", err), h.window)
+	}
+}
+
+// unfinishedJournals returns the journals of race sessions that were not
+// closed normally, oldest first
+func (a *App) unfinishedJournals() ([]string, error) {
+	dir, err := a.journalDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journals: %v", err)
+	}
+	sort.Strings(paths)
+
+	unfinished := make([]string, 0)
+	unreadable := make([]string, 0)
+	for _, path := range paths {
+		events, err := timing.ReadJournal(path)
+		if err != nil {
+			unreadable = append(unreadable, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+			continue
+		}
+		if !timing.Finished(events) {
+			unfinished = append(unfinished, path)
+		}
+	}
+	if len(unreadable) > 0 {
+		return unfinished, fmt.Errorf("some race journals cannot be recovered:\n%s", strings.Join(unreadable, "\n"))
+	}
+	return unfinished, nil
+}
+
+// offerRecovery asks whether to replay race sessions that were still open
+// when the app last stopped, such as after a crash. It is offered once a
+// regatta is loaded, since a recovered race is approved and saved in it.
+func (a *App) offerRecovery() {
+	unfinished, err := a.unfinishedJournals()
+	if err != nil {
+		dialog.ShowError(err, a.window)
+	}
+
+	// Journals of the race windows open now are still being written
+	open := make(map[string]bool)
+	for _, raceApp := range a.raceWindows {
+		if raceApp.journal != nil {
+			open[raceApp.journal.Path()] = true
+		}
+	}
+	for _, h := range a.headRaceWindows {
+		if h.journal != nil {
+			open[h.journal.Path()] = true
+		}
+	}
+	paths := make([]string, 0, len(unfinished))
+	for _, path := range unfinished {
+		if !open[path] {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	races := make([]string, len(paths))
+	for i, path := range paths {
+		races[i] = strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	}
+	message := fmt.Sprintf("%d race session(s) were not closed when Regatta Clock last stopped:\n%s\n\nReplay them into race windows?",
+		len(paths), strings.Join(races, "\n"))
+
+	dialog.ShowConfirm("Recover Races", message, func(replay bool) {
+		for _, path := range paths {
+			if !replay {
+				if err := a.discardJournal(path); err != nil {
+					dialog.ShowError(err, a.window)
+				}
+				continue
+			}
+			if err := a.recoverRace(path); err != nil {
+				dialog.ShowError(err, a.window)
+			}
+		}
+	}, a.window)
+}
+
+// recoverRace replays a journal into a new race window, which carries on
+// writing to the same journal
+func (a *App) recoverRace(path string) error {
+	events, err := timing.ReadJournal(path)
+	if err != nil {
+		return err
+	}
+	if events[0].Head {
+		return a.recoverHeadRace(path, events)
+	}
+	var race RaceData
+	if err := json.Unmarshal(events[0].Meta, &race); err != nil {
+		return fmt.Errorf("failed to read the race in journal %s: %v", filepath.Base(path), err)
+	}
+	// The journal is kept unfinished, to be offered again, if its race
+	// cannot be recovered into the loaded regatta
+	if a.findRace(race.Key()) == nil {
+		return fmt.Errorf("%s in journal %s is not in the loaded regatta", race.Key(), filepath.Base(path))
+	}
+	if _, open := a.raceWindows[race.Key()]; open {
+		return fmt.Errorf("%s is already open, so journal %s was not recovered", race.Key(), filepath.Base(path))
+	}
+	session, err := timing.ReplayJournal(events)
+	if err != nil {
+		return err
+	}
+	journal, err := timing.OpenJournal(path)
+	if err != nil {
+		return err
+	}
+	log := timing.NewEventLog(events, journal)
+	session.SetRecorder(log)
+
+	a.restoreRaceWindow(race, session, log, journal)
+	return nil
+}
+
+// recoverHeadRace replays a head race journal into a new head race window,
+// which carries on writing to the same journal
+func (a *App) recoverHeadRace(path string, events []timing.Event) error {
+	var meta headRaceMeta
+	if err := json.Unmarshal(events[0].Meta, &meta); err != nil {
+		return fmt.Errorf("failed to read the head race in journal %s: %v", filepath.Base(path), err)
+	}
+	// As with races, the journal is kept to be offered again
+	for _, entry := range meta.Entries {
+		if a.findRace(entry.Race) == nil {
+			return fmt.Errorf("%s in head race journal %s is not in the loaded regatta", entry.Race, filepath.Base(path))
+		}
+	}
+	if _, open := a.headRaceWindows[meta.Session]; open {
+		return fmt.Errorf("head race %s is already open, so journal %s was not recovered", meta.Session, filepath.Base(path))
+	}
+	session, err := timing.ReplayHeadJournal(events)
+	if err != nil {
+		return err
+	}
+	journal, err := timing.OpenJournal(path)
+	if err != nil {
+		return err
+	}
+	log := timing.NewEventLog(events, journal)
+	session.SetRecorder(log)
+
+	a.showHeadRace(meta, session, log, journal)
+	return nil
+}
+
+// resumeRace replays a race's earlier session events, such as those saved
+// in a project, into a new race window with a new journal
+func (a *App) resumeRace(race RaceData, events []timing.Event) error {
+	// The earlier session was closed, but this one carries on from it
+	resumed := make([]timing.Event, 0, len(events))
+	for _, event := range events {
+		if event.Kind != timing.EventClosed {
+			resumed = append(resumed, event)
+		}
+	}
+	session, err := timing.ReplayJournal(resumed)
+	if err != nil {
+		return err
+	}
+
+	journal, journalErr := a.startJournal(race)
+	log := timing.NewEventLog(nil, journalRecorder(journal))
+	for _, event := range resumed {
+		log.Record(event)
+	}
+	session.SetRecorder(log)
+
+	raceApp := a.restoreRaceWindow(race, session, log, journal)
+	if journalErr != nil {
+		raceApp.warnNotJournaled(journalErr)
+	}
+	return nil
+}
+
+// resumeHeadRace replays a head race's earlier session events, such as those
+// saved in a project, into a new head race window with a new journal
+func (a *App) resumeHeadRace(events []timing.Event) error {
+	resumed := make([]timing.Event, 0, len(events))
+	for _, event := range events {
+		if event.Kind != timing.EventClosed {
+			resumed = append(resumed, event)
+		}
+	}
+	session, err := timing.ReplayHeadJournal(resumed)
+	if err != nil {
+		return err
+	}
+	var meta headRaceMeta
+	if err := json.Unmarshal(resumed[0].Meta, &meta); err != nil {
+		return fmt.Errorf("failed to read the head race: %v", err)
+	}
+
+	journal, journalErr := a.startHeadJournal(meta.Session)
+	log := timing.NewEventLog(nil, journalRecorder(journal))
+	for _, event := range resumed {
+		log.Record(event)
+	}
+	session.SetRecorder(log)
+
+	h := a.showHeadRace(meta, session, log, journal)
+	if journalErr != nil {
+		h.warnNotJournaled(journalErr)
+	}
+	return nil
+}
+
+// restoreRaceWindow opens a race window for a replayed session and restores
+// the winning time, clock state and approval shown in it
+func (a *App) restoreRaceWindow(race RaceData, session *timing.RaceSession, log *timing.EventLog, journal *timing.Journal) *App {
+	raceApp := a.showRaceClock(race, session, log, journal)
+	results := session.Results()
+	if results.Calibrated {
+		raceApp.refreshing = true
+		raceApp.winningTime.SetText(results.Rules.Precision.Format(results.WinningTime))
+		raceApp.refreshing = false
+	}
+	if results.State == timing.StateRunning {
+		raceApp.raceNumber.Disable()
+		raceApp.winningTime.Disable()
+	}
+	raceApp.refreshContent()
+	raceApp.updateRefereeButton()
+
+	// Approvals and protests are replayed in order, each approval closing
+	// the protest before it
+	reviewed := RaceData{}
+	for _, event := range log.Events() {
+		switch event.Kind {
+		case timing.EventApproved:
+			approval := raceApp.approvalFromEvent(race, event)
+			closeProtest(&reviewed, approval)
+			reviewed.Approvals = append(reviewed.Approvals, approval)
+		case timing.EventProtested:
+			reviewed.Protests = append(reviewed.Protests, protestFromEvent(event))
+		}
+	}
+	if len(reviewed.Approvals) > 0 {
+		raceApp.restoreApproval(race, reviewed)
+	}
+	return raceApp
+}
+
+// restoreApproval restores a recovered race's approvals and protests in the
+// regatta data and marks it approved again, unless it is under review or
+// its results were amended afterwards
+func (a *App) restoreApproval(race RaceData, reviewed RaceData) {
+	restored := a.findRace(race.Key())
+	if restored == nil {
+		return
+	}
+	// The session's events hold every approval since the race was first timed
+	if len(reviewed.Approvals) >= len(restored.Approvals) {
+		restored.Approvals = reviewed.Approvals
+	}
+	if len(reviewed.Protests) >= len(restored.Protests) {
+		restored.Protests = reviewed.Protests
+	}
+	if restored.OpenProtest() != nil {
+		restored.Approved = false
+		return
+	}
+	restored.Approved = true
+	a.enableApprovedActions()
+	a.checkAmendment()
+	if restored.Approved {
+		a.storeResults(restored)
+	}
+}
+
+// discardJournal marks a journal closed when its race is not recovered, so it
+// is not offered again
+func (a *App) discardJournal(path string) error {
+	journal, err := timing.OpenJournal(path)
+	if err != nil {
+		return err
+	}
+	journal.Record(timing.Event{Kind: timing.EventClosed, At: time.Now()})
+	return journal.Close()
+}
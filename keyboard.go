@@ -5,23 +5,39 @@ import (
 )
 
 type keyboardHandler struct {
-	startFunc func()
-	lapFunc   func()
+	startFunc  func()
+	signalFunc func()
+	lapFunc    func()
+	splitFuncs []func() // One per split station with a key
 }
 
 func (h *keyboardHandler) TypedKey(event *fyne.KeyEvent) {
 	switch event.Name {
 	case fyne.KeyF2:
 		h.startFunc()
+	case fyne.KeyF3:
+		h.signalFunc()
 	case fyne.KeyF4:
 		h.lapFunc()
+	default:
+		for station, key := range splitKeys {
+			if event.Name == key && station < len(h.splitFuncs) {
+				h.splitFuncs[station]()
+			}
+		}
 	}
 }
 
 func (a *App) setupKeyboardHandler() func(*fyne.KeyEvent) {
 	handler := &keyboardHandler{
-		startFunc: a.startFunc(),
-		lapFunc:   a.lapFunc(),
+		startFunc:  a.startFunc(),
+		signalFunc: a.startSignalFunc(),
+		lapFunc:    a.lapFunc(),
+	}
+	for station := range a.session.SplitStations() {
+		if station < len(splitKeys) {
+			handler.splitFuncs = append(handler.splitFuncs, a.splitFunc(station))
+		}
 	}
 	return handler.TypedKey
 }
@@ -1,23 +1,34 @@
 package regattaClock
 
 import (
+	"strconv"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
 )
 
-type lapTime struct {
-	number         int
-	time           string
-	calculatedTime string
-	oof            string
-}
+const (
+	nextPlace = "Next Place" // Returns a lane to finish order placing
+	deadHeat  = "Dead Heat"  // Shares a place with the capture before
+)
+
+// Capture list sizing
+const (
+	lapTableWidth = 1200
+	lapRowHeight  = 44
+)
 
+// LapTableRow holds the widgets of a capture list row
 type LapTableRow struct {
-	oofEntry    *widget.Entry
-	placeButton *widget.Button
-	splitEntry  *widget.Entry
-	timeLabel   *widget.Label
+	index        int // Capture shown in the row, -1 before it is bound
+	oofEntry     *widget.Entry
+	placeButton  *widget.Button
+	splitEntry   *widget.Entry
+	timeLabel    *widget.Label
+	editControls []*widget.Button // Delete, move up and move down
 }
 
 type RaceTreeNode struct {
@@ -30,22 +41,7 @@ type RaceTreeNode struct {
 func (a *App) raceResults() *fyne.Container {
 	// Initialize table data if not already done
 	if a.resultsTable == nil {
-		a.resultsTable = [][]string{
-			{"", "Lane 1", "Lane 2", "Lane 3", "Lane 4", "Lane 5", "Lane 6"},
-			{"", "", "", "", "", "", ""},
-			{"Place", "", "", "", "", "", ""},
-			{"Split", "", "", "", "", "", ""},
-			{"Time", "", "", "", "", "", ""},
-			{"", "", "", "", "", "", ""}, // Add fifth data row
-		}
-	}
-
-	// Ensure we have enough rows for the data
-	if len(a.resultsTable) < 6 {
-		// Add any missing rows
-		for i := len(a.resultsTable); i < 6; i++ {
-			a.resultsTable = append(a.resultsTable, make([]string, 7))
-		}
+		a.resultsTable = newResultsTable(a.laneCount, a.session.SplitStations())
 	}
 
 	list := widget.NewTable(
@@ -65,7 +61,7 @@ func (a *App) raceResults() *fyne.Container {
 }
 
 func (a *App) lapHeader() *fyne.Container {
-	header := container.NewGridWithColumns(4)
+	header := container.NewGridWithColumns(5)
 
 	oofHeader := widget.NewLabel("OOF")
 	oofHeader.TextStyle = fyne.TextStyle{Bold: true}
@@ -73,7 +69,7 @@ func (a *App) lapHeader() *fyne.Container {
 	placeHeader := widget.NewLabel("Place")
 	placeHeader.TextStyle = fyne.TextStyle{Bold: true}
 
-	splitHeader := widget.NewLabel("Split")
+	splitHeader := widget.NewLabel("Raw Time")
 	splitHeader.TextStyle = fyne.TextStyle{Bold: true}
 
 	timeHeader := widget.NewLabel("Time")
@@ -83,42 +79,154 @@ func (a *App) lapHeader() *fyne.Container {
 	header.Add(placeHeader)
 	header.Add(splitHeader)
 	header.Add(timeHeader)
+	header.Add(widget.NewLabel(emptyString)) // Edit controls
 
 	return header
 }
 
-func (a *App) lapTable() *fyne.Container {
-	tablesContainer := container.NewVBox()
-	tablesContainer.Add(a.lapHeader())
-
-	a.tableRows = make([]LapTableRow, 6)
-	for i := 0; i < 6; i++ {
-		row := container.NewGridWithColumns(4)
-
-		// Create widgets for each column
-		oofEntry := widget.NewEntry()
-		placeButton := widget.NewButton(emptyString, nil)
-		placeButton.Importance = widget.MediumImportance
-		placeButton.Resize(fyne.NewSize(100, 30)) // Set minimum size
-		splitEntry := widget.NewEntry()
-		timeLabel := widget.NewLabel(emptyString)
-
-		// Add widgets to row
-		row.Add(oofEntry)
-		row.Add(placeButton)
-		row.Add(splitEntry)
-		row.Add(timeLabel)
-
-		// Store the widgets
-		a.tableRows[i] = LapTableRow{
-			oofEntry:    oofEntry,
-			placeButton: placeButton,
-			splitEntry:  splitEntry,
-			timeLabel:   timeLabel,
+func (a *App) lapTable() fyne.CanvasObject {
+	a.snapshot = a.session.Results()
+	a.lapRows = make(map[fyne.CanvasObject]*LapTableRow)
+
+	// Rows are recycled as the list scrolls, so there is no limit on the
+	// number of captures
+	a.captureList = widget.NewList(
+		func() int {
+			return len(a.snapshot.Captures)
+		},
+		a.newLapRow,
+		a.bindLapRow,
+	)
+
+	// Room for one capture per lane before the list scrolls
+	height := float32(a.laneCount+1) * lapRowHeight
+	return container.NewGridWrap(
+		fyne.NewSize(lapTableWidth, height),
+		container.NewBorder(a.lapHeader(), nil, nil, nil, a.captureList),
+	)
+}
+
+// newLapRow creates the widgets for a capture list row. Its handlers act on
+// whichever capture the row is currently bound to.
+func (a *App) newLapRow() fyne.CanvasObject {
+	row := &LapTableRow{index: -1}
+
+	row.oofEntry = widget.NewEntry()
+	row.placeButton = widget.NewButton(emptyString, nil)
+	row.placeButton.Importance = widget.MediumImportance
+	row.splitEntry = widget.NewEntry()
+	row.timeLabel = widget.NewLabel(emptyString)
+
+	// Edits go straight to the timing session
+	row.oofEntry.OnChanged = func(text string) {
+		a.assignLane(row.index, text)
+	}
+	row.oofEntry.OnSubmitted = func(string) {
+		a.focusNextOOF(row.index)
+	}
+	row.placeButton.OnTapped = func() {
+		a.editPlace(row.index)
+	}
+	row.splitEntry.OnChanged = func(text string) {
+		a.editSplit(row.index, text)
+	}
+	row.editControls = []*widget.Button{
+		widget.NewButtonWithIcon(emptyString, theme.DeleteIcon(), func() {
+			a.deleteCapture(row.index)
+		}),
+		widget.NewButtonWithIcon(emptyString, theme.MoveUpIcon(), func() {
+			a.moveCapture(row.index, -1)
+		}),
+		widget.NewButtonWithIcon(emptyString, theme.MoveDownIcon(), func() {
+			a.moveCapture(row.index, 1)
+		}),
+	}
+
+	item := container.NewGridWithColumns(5,
+		row.oofEntry,
+		row.placeButton,
+		row.splitEntry,
+		row.timeLabel,
+		container.NewHBox(row.editControls[0], row.editControls[1], row.editControls[2]),
+	)
+	a.lapRows[item] = row
+	return item
+}
+
+// bindLapRow shows a capture from the current snapshot in a capture list row
+func (a *App) bindLapRow(id widget.ListItemID, item fyne.CanvasObject) {
+	row := a.lapRows[item]
+	if row == nil || id >= len(a.snapshot.Captures) {
+		return
+	}
+	// Leave an entry alone while it is being typed in
+	focused := a.window.Canvas().Focused()
+	editing := func(entry *widget.Entry) bool {
+		return row.index == id && focused == entry
+	}
+	// A row released when scrolled out of view may still hold this capture
+	for _, other := range a.lapRows {
+		if other != row && other.index == id {
+			other.index = -1
 		}
+	}
+	row.index = id
+
+	capture := a.snapshot.Captures[id]
+	precision := a.snapshot.Rules.Precision
+	running := a.snapshot.State == timing.StateRunning
+
+	// Setting the entries below fires their OnChanged handlers
+	a.refreshing = true
+	if !editing(row.oofEntry) {
+		oof := emptyString
+		if capture.Lane != 0 {
+			oof = strconv.Itoa(capture.Lane)
+		}
+		row.oofEntry.SetText(oof)
+	}
+	if !editing(row.splitEntry) {
+		row.splitEntry.SetText(precision.Format(capture.Elapsed))
+	}
+	a.refreshing = false
 
-		// Add row to container
-		tablesContainer.Add(row)
+	if running {
+		row.oofEntry.Disable()
+	} else {
+		row.oofEntry.Enable()
+	}
+	// Captures can only be deleted or reordered once the race is stopped
+	for _, control := range row.editControls {
+		if a.snapshot.State == timing.StateStopped {
+			control.Enable()
+		} else {
+			control.Disable()
+		}
+	}
+
+	row.placeButton.SetText(capture.Place)
+	// Flag captures that tie with the one before so a dead heat can be declared
+	if capture.CanDeadHeat {
+		row.placeButton.Importance = widget.WarningImportance
+	} else {
+		row.placeButton.Importance = widget.MediumImportance
+	}
+	row.placeButton.Refresh()
+
+	timeText := precision.Format(capture.Time)
+	if capture.Manual {
+		timeText += " (manual)"
+	}
+	row.timeLabel.SetText(timeText)
+}
+
+// lapRow returns the capture list row showing a capture, or nil if the
+// capture is scrolled out of view
+func (a *App) lapRow(index int) *LapTableRow {
+	for _, row := range a.lapRows {
+		if row.index == index {
+			return row
+		}
 	}
-	return tablesContainer
+	return nil
 }
@@ -0,0 +1,182 @@
+package regattaClock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// WorkbookLayout describes where a draw workbook keeps the regatta title and
+// race blocks, so the reader can handle table variants from different hosts.
+//
+// A layout profile is a JSON file with the same field names, e.g.
+//
+//	{
+//	  "name": "Standard",
+//	  "titleRange": "A1:I2",
+//	  "dateCell": "",
+//	  "raceColumn": "A",
+//	  "raceRows": 5,
+//	  "eventColumn": "C",
+//	  "firstLaneColumn": "D",
+//	  "lanes": 6,
+//	  "schoolRow": 0,
+//	  "infoRow": 1,
+//	  "placeRow": 2,
+//	  "splitRow": 3,
+//	  "timeRow": 4,
+//	  "splitRows": []
+//	}
+//
+// Omitted fields take the standard layout's value. Row fields are offsets
+// from the first row of a race block. When dateCell is empty the date is read
+// from the end of the title, after a run of spaces.
+//
+// The split row holds each lane's times at every split station. splitRows
+// gives a row of its own to each station, nearest the start first, written
+// with the lane's time and segment there; e.g. [5, 6] puts 500 m and 1000 m
+// splits below the time in a 7-row block.
+type WorkbookLayout struct {
+	Name            string `json:"name"`
+	TitleRange      string `json:"titleRange"`      // Cell or merged range holding the regatta title
+	DateCell        string `json:"dateCell"`        // Cell holding the date; empty if the date follows the title
+	RaceColumn      string `json:"raceColumn"`      // Column of the merged race number cells
+	RaceRows        int    `json:"raceRows"`        // Number of rows in each race block
+	EventColumn     string `json:"eventColumn"`     // Column holding the class/flight text
+	FirstLaneColumn string `json:"firstLaneColumn"` // Column of lane 1
+	Lanes           int    `json:"lanes"`           // Number of lane columns
+	SchoolRow       int    `json:"schoolRow"`
+	InfoRow         int    `json:"infoRow"`
+	PlaceRow        int    `json:"placeRow"`
+	SplitRow        int    `json:"splitRow"`
+	TimeRow         int    `json:"timeRow"`
+	SplitRows       []int  `json:"splitRows"` // Row of each split station, nearest the start first
+}
+
+// DefaultLayout returns the standard layout: title in A1:I2, 5-row race blocks
+// in column A, class/flight in column C and lanes 1-6 in columns D-I
+func DefaultLayout() *WorkbookLayout {
+	return &WorkbookLayout{
+		Name:            "Standard",
+		TitleRange:      "A1:I2",
+		RaceColumn:      "A",
+		RaceRows:        5,
+		EventColumn:     "C",
+		FirstLaneColumn: "D",
+		Lanes:           6,
+		SchoolRow:       0,
+		InfoRow:         1,
+		PlaceRow:        2,
+		SplitRow:        3,
+		TimeRow:         4,
+	}
+}
+
+// LoadLayout reads a JSON layout profile, filling omitted fields from the
+// standard layout
+func LoadLayout(filePath string) (*WorkbookLayout, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout file: %v", err)
+	}
+
+	layout := DefaultLayout()
+	layout.Name = emptyString
+	if err := json.Unmarshal(content, layout); err != nil {
+		return nil, fmt.Errorf("failed to parse layout file: %v", err)
+	}
+	if layout.Name == emptyString {
+		layout.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	layout.TitleRange = strings.ToUpper(layout.TitleRange)
+	layout.DateCell = strings.ToUpper(layout.DateCell)
+	layout.RaceColumn = strings.ToUpper(layout.RaceColumn)
+	layout.EventColumn = strings.ToUpper(layout.EventColumn)
+	layout.FirstLaneColumn = strings.ToUpper(layout.FirstLaneColumn)
+
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Validate checks that the layout's columns and rows are usable
+func (l *WorkbookLayout) Validate() error {
+	for name, col := range map[string]string{
+		"raceColumn":      l.RaceColumn,
+		"eventColumn":     l.EventColumn,
+		"firstLaneColumn": l.FirstLaneColumn,
+	} {
+		if _, err := excelize.ColumnNameToNumber(col); err != nil {
+			return fmt.Errorf("layout %s: invalid %s %q", l.Name, name, col)
+		}
+	}
+	if l.TitleRange != emptyString {
+		if _, _, err := l.titleCells(); err != nil {
+			return fmt.Errorf("layout %s: invalid titleRange %q", l.Name, l.TitleRange)
+		}
+	}
+	if l.DateCell != emptyString {
+		if _, _, err := excelize.CellNameToCoordinates(l.DateCell); err != nil {
+			return fmt.Errorf("layout %s: invalid dateCell %q", l.Name, l.DateCell)
+		}
+	}
+	if l.RaceRows < 1 {
+		return fmt.Errorf("layout %s: raceRows must be at least 1", l.Name)
+	}
+	if l.Lanes < 1 || l.Lanes > maxLaneCount {
+		return fmt.Errorf("layout %s: lanes must be between 1 and %d", l.Name, maxLaneCount)
+	}
+	for name, row := range map[string]int{
+		"schoolRow": l.SchoolRow,
+		"infoRow":   l.InfoRow,
+		"placeRow":  l.PlaceRow,
+		"splitRow":  l.SplitRow,
+		"timeRow":   l.TimeRow,
+	} {
+		if row < 0 || row >= l.RaceRows {
+			return fmt.Errorf("layout %s: %s must be between 0 and %d", l.Name, name, l.RaceRows-1)
+		}
+	}
+	used := map[int]bool{l.SchoolRow: true, l.InfoRow: true, l.PlaceRow: true, l.SplitRow: true, l.TimeRow: true}
+	for _, row := range l.SplitRows {
+		if row < 0 || row >= l.RaceRows {
+			return fmt.Errorf("layout %s: splitRows must be between 0 and %d", l.Name, l.RaceRows-1)
+		}
+		if used[row] {
+			return fmt.Errorf("layout %s: split row %d is already used", l.Name, row)
+		}
+		used[row] = true
+	}
+	return nil
+}
+
+// titleCells returns the start and end cells of the title range
+func (l *WorkbookLayout) titleCells() (string, string, error) {
+	start, end, found := strings.Cut(l.TitleRange, ":")
+	if !found {
+		end = start
+	}
+	for _, cell := range []string{start, end} {
+		if _, _, err := excelize.CellNameToCoordinates(cell); err != nil {
+			return emptyString, emptyString, err
+		}
+	}
+	return start, end, nil
+}
+
+// laneColumn returns the column name of a lane (1-based)
+func (l *WorkbookLayout) laneColumn(lane int) string {
+	first, _ := excelize.ColumnNameToNumber(l.FirstLaneColumn)
+	name, _ := excelize.ColumnNumberToName(first + lane - 1)
+	return name
+}
+
+// cellName returns the name of the cell in column col at row
+func cellName(col string, row int) string {
+	return fmt.Sprintf("%s%d", col, row)
+}
@@ -0,0 +1,109 @@
+package regattaClock
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		json    string
+		want    func(*WorkbookLayout) // Changes from the standard layout
+		wantErr bool
+	}{
+		{
+			name: "omitted fields take the standard layout",
+			file: "Standard.json",
+			json: `{"name": "Standard"}`,
+			want: func(*WorkbookLayout) {},
+		},
+		{
+			name: "name from the file name",
+			file: "Seven Lanes.json",
+			json: `{"lanes": 7, "firstLaneColumn": "e", "splitRows": [5], "raceRows": 6}`,
+			want: func(l *WorkbookLayout) {
+				l.Name = "Seven Lanes"
+				l.Lanes = 7
+				l.FirstLaneColumn = "E"
+				l.RaceRows = 6
+				l.SplitRows = []int{5}
+			},
+		},
+		{
+			name: "date in its own cell",
+			file: "Dated.json",
+			json: `{"titleRange": "b1", "dateCell": "b2"}`,
+			want: func(l *WorkbookLayout) {
+				l.Name = "Dated"
+				l.TitleRange = "B1"
+				l.DateCell = "B2"
+			},
+		},
+		{name: "invalid JSON", file: "Broken.json", json: `{"lanes": `, wantErr: true},
+		{name: "invalid layout", file: "Wide.json", json: `{"lanes": 20}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatalf("failed to write layout file: %v", err)
+			}
+			got, err := LoadLayout(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want := DefaultLayout()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadLayout() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadLayoutMissingFile(t *testing.T) {
+	if _, err := LoadLayout(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadLayout() of a missing file succeeded, want an error")
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*WorkbookLayout)
+		wantErr bool
+	}{
+		{name: "standard", change: func(*WorkbookLayout) {}},
+		{name: "no title", change: func(l *WorkbookLayout) { l.TitleRange = emptyString }},
+		{name: "split rows", change: func(l *WorkbookLayout) { l.RaceRows = 7; l.SplitRows = []int{5, 6} }},
+		{name: "invalid column", change: func(l *WorkbookLayout) { l.RaceColumn = "1" }, wantErr: true},
+		{name: "invalid title range", change: func(l *WorkbookLayout) { l.TitleRange = "A1:ZZZZ" }, wantErr: true},
+		{name: "invalid date cell", change: func(l *WorkbookLayout) { l.DateCell = "B" }, wantErr: true},
+		{name: "no race rows", change: func(l *WorkbookLayout) { l.RaceRows = 0 }, wantErr: true},
+		{name: "no lanes", change: func(l *WorkbookLayout) { l.Lanes = 0 }, wantErr: true},
+		{name: "too many lanes", change: func(l *WorkbookLayout) { l.Lanes = maxLaneCount + 1 }, wantErr: true},
+		{name: "row outside the block", change: func(l *WorkbookLayout) { l.TimeRow = 5 }, wantErr: true},
+		{name: "split row outside the block", change: func(l *WorkbookLayout) { l.SplitRows = []int{5} }, wantErr: true},
+		{name: "split row on the time row", change: func(l *WorkbookLayout) { l.SplitRows = []int{4} }, wantErr: true},
+		{name: "split rows repeated", change: func(l *WorkbookLayout) { l.RaceRows = 7; l.SplitRows = []int{5, 5} }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := DefaultLayout()
+			tt.change(layout)
+			err := layout.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,14 @@ package regattaClock
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
 )
 
 func (a *App) loadExcel(fromStartup bool) {
@@ -28,50 +33,225 @@ func (a *App) loadExcel(fromStartup bool) {
 
 		// Verify file extension
 		uri := reader.URI()
-		if uri.Extension() != ".xlsx" {
-			dialog.ShowError(fmt.Errorf("only .xlsx files are supported"), a.window)
+		if ext := strings.ToLower(uri.Extension()); ext != ".xlsx" && ext != ".csv" {
+			dialog.ShowError(fmt.Errorf("only .xlsx and .csv files are supported"), a.window)
 			return
 		}
 
 		// Get the file path from the URI
 		filePath := uri.Path()
-		fmt.Printf("Debug: Importing Excel file: %s\n", filePath)
 
-		// Read the Excel file
-		regattaData, err := ReadExcelFile(filePath)
-		if err != nil {
-			dialog.ShowError(err, a.window)
-			return
-		}
+		a.withImportOptions(filePath, func(opts ImportOptions) {
+			a.importDraw(filePath, opts)
+		})
+	}, a.window)
+}
+
+// withImportOptions calls onChosen with the options to read filePath with.
+// Workbooks may need a host-specific layout or sheet selection; CSV draws do not.
+func (a *App) withImportOptions(filePath string, onChosen func(ImportOptions)) {
+	if strings.ToLower(filepath.Ext(filePath)) == ".xlsx" {
+		a.chooseImportOptions(filePath, onChosen)
+		return
+	}
+	onChosen(ImportOptions{})
+}
+
+// chooseImportOptions asks which workbook layout and sheets to import with,
+// skipping the question when there is only one choice of each
+func (a *App) chooseImportOptions(filePath string, onChosen func(ImportOptions)) {
+	sheets, err := ExcelSheetNames(filePath)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	if len(a.layouts) <= 1 && len(sheets) <= 1 {
+		onChosen(ImportOptions{Layout: a.layouts[0]})
+		return
+	}
+
+	form := container.NewVBox()
+
+	names := make([]string, len(a.layouts))
+	for i, layout := range a.layouts {
+		names[i] = layout.Name
+	}
+	layoutSelect := widget.NewSelect(names, nil)
+	layoutSelect.SetSelectedIndex(0)
+	if len(a.layouts) > 1 {
+		form.Add(widget.NewLabel("Select the layout of the regatta table:"))
+		form.Add(layoutSelect)
+	}
 
-		// Store the regatta data
-		a.regattaData = regattaData
+	// Each sheet is a day or session; import them all by default
+	sheetCheck := widget.NewCheckGroup(sheets, nil)
+	sheetCheck.SetSelected(sheets)
+	if len(sheets) > 1 {
+		form.Add(widget.NewLabel("Select the days or sessions to import:"))
+		form.Add(sheetCheck)
+	}
 
-		// Calculate scheduled races (races with at least one lane)
-		scheduledRaces := 0
-		for _, race := range regattaData.Races {
-			if len(race.Lanes) > 0 {
-				scheduledRaces++
+	dialog.ShowCustomConfirm(
+		"Import Options",
+		"Import",
+		"Cancel",
+		form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
 			}
+			// Keep the selected sheets in workbook order
+			selected := make([]string, 0, len(sheets))
+			for _, sheet := range sheets {
+				for _, checked := range sheetCheck.Selected {
+					if sheet == checked {
+						selected = append(selected, sheet)
+						break
+					}
+				}
+			}
+			if len(selected) == 0 {
+				dialog.ShowError(fmt.Errorf("select at least one sheet to import"), a.window)
+				return
+			}
+			onChosen(ImportOptions{
+				Layout: a.layouts[layoutSelect.SelectedIndex()],
+				Sheets: selected,
+			})
+		},
+		a.window,
+	)
+}
+
+func (a *App) importDraw(filePath string, opts ImportOptions) {
+	// Read the Excel workbook or CSV draw
+	regattaData, err := ReadDrawFile(filePath, opts)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	if !a.timingWindowsOpen() {
+		a.useDraw(regattaData)
+		return
+	}
+	dialog.ShowConfirm(
+		"Import",
+		"Importing a draw closes the open race and head race windows. Continue?",
+		func(confirmed bool) {
+			if confirmed {
+				a.useDraw(regattaData)
+			}
+		},
+		a.window,
+	)
+}
+
+// useDraw replaces the loaded regatta with a newly imported draw
+func (a *App) useDraw(regattaData *RegattaData) {
+	// A new draw starts a new project, without the last one's race windows
+	a.closeTimingWindows()
+	a.projectPath = emptyString
+	a.raceLogs = make(map[RaceKey]*timing.EventLog)
+	a.headRaceLogs = make(map[string]*timing.EventLog)
+	a.setRegattaData(regattaData)
+	a.offerRecovery()
+
+	// Show success message, or the problems found while importing
+	if len(regattaData.Diagnostics) == 0 {
+		dialog.ShowInformation("Import", "Successfully read draw file", a.window)
+		return
+	}
+	a.showImportDiagnostics(regattaData.Diagnostics)
+}
+
+// setRegattaData stores the regatta data and shows its title, date and race tree
+func (a *App) setRegattaData(regattaData *RegattaData) {
+	// Store the regatta data, where open race windows store their results too
+	a.regattaData = regattaData
+	for _, raceApp := range a.raceWindows {
+		raceApp.regattaData = regattaData
+	}
+
+	// Calculate scheduled races (races with at least one lane)
+	scheduledRaces := 0
+	for _, race := range regattaData.Races {
+		if len(race.Lanes) > 0 {
+			scheduledRaces++
 		}
+	}
 
-		fmt.Printf("Debug: Successfully loaded regatta data - %d total races, %d scheduled races\n",
-			len(regattaData.Races), scheduledRaces)
-		fmt.Printf("Debug: Regatta Name: %s\n", regattaData.RegattaName)
-		fmt.Printf("Debug: Regatta Date: %s\n", regattaData.Date)
+	// Update the title, scheduled races count, and date
+	a.regattaTitle.Text = regattaData.RegattaName
+	a.scheduledRaces.Text = fmt.Sprintf("Scheduled Races: %d", scheduledRaces)
+	a.regattaDate.Text = regattaData.DisplayDate()
+	a.regattaTitle.Refresh()
+	a.scheduledRaces.Refresh()
+	a.regattaDate.Refresh()
 
-		// Update the title, scheduled races count, and date
-		a.regattaTitle.Text = regattaData.RegattaName
-		a.scheduledRaces.Text = fmt.Sprintf("Scheduled Races: %d", scheduledRaces)
-		a.regattaDate.Text = regattaData.Date
-		a.regattaTitle.Refresh()
-		a.scheduledRaces.Refresh()
-		a.regattaDate.Refresh()
+	// Show the race tree
+	a.showRaceTree()
+}
+
+// showImportDiagnostics lists the problems found while importing a draw
+func (a *App) showImportDiagnostics(diagnostics []ImportDiagnostic) {
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(diagnostics) + 1, 3
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText([]string{"Severity", "Cell", "Problem"}[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			d := diagnostics[id.Row-1]
+			label.SetText([]string{d.Severity.String(), d.Cell, d.Message}[id.Col])
+		},
+	)
+	table.SetColumnWidth(0, 90)
+	table.SetColumnWidth(1, 110)
+	table.SetColumnWidth(2, 560)
+
+	summary := fmt.Sprintf("The draw was imported with %d problem(s). Check them before racing starts.", len(diagnostics))
+	content := container.NewBorder(widget.NewLabel(summary), nil, nil, nil, table)
+
+	importDialog := dialog.NewCustom("Import Diagnostics", "Close", content, a.window)
+	importDialog.Resize(fyne.NewSize(800, 500))
+	importDialog.Show()
+}
+
+func (a *App) loadLayout() {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if reader == nil {
+			// User cancelled
+			return
+		}
+		defer reader.Close()
 
-		// Show success message
-		dialog.ShowInformation("Import", "Successfully read Excel file", a.window)
+		layout, err := LoadLayout(reader.URI().Path())
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
 
-		// Show the race tree
-		a.showRaceTree()
+		// Replace a profile with the same name, otherwise add it
+		for i, existing := range a.layouts {
+			if existing.Name == layout.Name {
+				a.layouts[i] = layout
+				dialog.ShowInformation("Workbook Layout", fmt.Sprintf("Updated layout %q", layout.Name), a.window)
+				return
+			}
+		}
+		a.layouts = append(a.layouts, layout)
+		dialog.ShowInformation("Workbook Layout", fmt.Sprintf("Loaded layout %q", layout.Name), a.window)
 	}, a.window)
 }
@@ -7,7 +7,18 @@ import (
 func (a *App) makeMenu() *fyne.MainMenu {
 
 	return fyne.NewMainMenu(fyne.NewMenu("Regatta Clock",
+		a.openProjectItem(),
+		a.saveProjectItem(),
+		a.saveProjectAsItem(),
+		fyne.NewMenuItemSeparator(),
 		a.importItem(),
+		a.reloadItem(),
+		a.layoutItem(),
+		a.timingItem(),
+		a.splitsItem(),
+		a.headRaceItem(),
+		a.exportItem(),
+		a.bookletItem(),
 		a.showWindowItem(),
 		fyne.NewMenuItemSeparator(),
 		a.exitItem(),
@@ -15,12 +26,72 @@ func (a *App) makeMenu() *fyne.MainMenu {
 
 }
 
+func (a *App) openProjectItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Open Project", func() {
+		a.openProject()
+	})
+}
+
+func (a *App) saveProjectItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Save Project", func() {
+		a.saveProject()
+	})
+}
+
+func (a *App) saveProjectAsItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Save Project As", func() {
+		a.saveProjectAs()
+	})
+}
+
 func (a *App) importItem() *fyne.MenuItem {
 	return fyne.NewMenuItem("Import Regatta Table", func() {
 		a.loadExcel(false)
 	})
 }
 
+func (a *App) reloadItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Reload Revised Draw", func() {
+		a.reloadDraw()
+	})
+}
+
+func (a *App) layoutItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Load Workbook Layout", func() {
+		a.loadLayout()
+	})
+}
+
+func (a *App) timingItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Timing Precision", func() {
+		a.showTimingRules()
+	})
+}
+
+func (a *App) splitsItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Split Stations", func() {
+		a.showSplitStations()
+	})
+}
+
+func (a *App) headRaceItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Time Head Race", func() {
+		a.openHeadRace()
+	})
+}
+
+func (a *App) exportItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Export Results", func() {
+		a.exportResults()
+	})
+}
+
+func (a *App) bookletItem() *fyne.MenuItem {
+	return fyne.NewMenuItem("Print Results Booklet", func() {
+		a.exportRegattaPDF()
+	})
+}
+
 func (a *App) showWindowItem() *fyne.MenuItem {
 	return fyne.NewMenuItem("Show Window", func() {
 		a.window.Show()
@@ -0,0 +1,211 @@
+package regattaClock
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConflictKind identifies what changed in a revised draw for a race or lane
+// that already has captured results
+type ConflictKind int
+
+const (
+	ConflictSchoolChanged ConflictKind = iota // A timed lane has a different school
+	ConflictLaneRemoved                       // A timed lane was scratched
+	ConflictLaneAdded                         // A lane was added to a timed race
+	ConflictRaceRemoved                       // A timed race is missing from the revision
+)
+
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictSchoolChanged:
+		return "School changed"
+	case ConflictLaneRemoved:
+		return "Lane removed"
+	case ConflictLaneAdded:
+		return "Lane added"
+	case ConflictRaceRemoved:
+		return "Race removed"
+	}
+	return fmt.Sprintf("ConflictKind(%d)", int(k))
+}
+
+// MergeConflict is a difference between the captured and revised draw that
+// needs a decision. The merged data keeps the captured side until the
+// conflict is resolved in favour of the revision.
+type MergeConflict struct {
+	Kind     ConflictKind
+	Race     RaceKey
+	Lane     int        // Zero for whole-race conflicts
+	Captured *RaceEntry // Lane as timed, nil if it did not exist
+	Revised  *RaceEntry // Lane in the revised draw, nil if it was removed
+	Message  string
+}
+
+// DrawMerge is the result of merging a revised draw into captured regatta data
+type DrawMerge struct {
+	Merged    *RegattaData
+	Conflicts []MergeConflict
+}
+
+// hasResult reports whether a lane has a captured place, split or time
+func (e RaceEntry) hasResult() bool {
+	return e.Place != emptyString || e.Split != emptyString || e.Time != emptyString || len(e.Splits) > 0
+}
+
+// hasResults reports whether the race has been approved, saved or has any
+// captured lane results
+func (r RaceData) hasResults() bool {
+	if r.Approved || r.Saved {
+		return true
+	}
+	for _, entry := range r.Lanes {
+		if entry.hasResult() {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeDraw merges a revised draw into the current regatta data, matching
+// races by session and race number. The revision supplies the draw, workbook and race
+// positions; approvals, protests, captured results, timing rules and split
+// stations are carried over from current.
+// Changes to lanes or races that already have results are returned as
+// conflicts, with the captured side kept until resolved.
+func MergeDraw(current *RegattaData, revised *RegattaData) *DrawMerge {
+	merged := *revised
+	merged.Timing = current.Timing
+	merged.Splits = current.Splits
+	merged.Races = make([]RaceData, 0, len(revised.Races))
+	merge := &DrawMerge{Merged: &merged}
+
+	currentRaces := make(map[RaceKey]RaceData)
+	for _, race := range current.Races {
+		currentRaces[race.Key()] = race
+	}
+
+	revisedRaces := make(map[RaceKey]bool)
+	for _, revisedRace := range revised.Races {
+		revisedRaces[revisedRace.Key()] = true
+		race := revisedRace
+		race.Lanes = make(map[int]RaceEntry)
+		for lane, entry := range revisedRace.Lanes {
+			race.Lanes[lane] = entry
+		}
+
+		captured, exists := currentRaces[revisedRace.Key()]
+		if exists {
+			race.Approved = captured.Approved
+			race.Approvals = captured.Approvals
+			race.Protests = captured.Protests
+			race.Saved = captured.Saved
+			merge.mergeLanes(&race, captured, revisedRace)
+		}
+		merged.Races = append(merged.Races, race)
+	}
+
+	// Keep timed races the revision dropped until the user decides
+	for _, race := range current.Races {
+		if revisedRaces[race.Key()] || !race.hasResults() {
+			continue
+		}
+		merged.Races = append(merged.Races, race)
+		merge.Conflicts = append(merge.Conflicts, MergeConflict{
+			Kind:    ConflictRaceRemoved,
+			Race:    race.Key(),
+			Message: fmt.Sprintf("%s has results but is not in the revised draw", race.Key()),
+		})
+	}
+
+	// Conflicts are listed in the order of the merged races
+	sortRaces(merged.Races)
+	order := make(map[RaceKey]int)
+	for i, race := range merged.Races {
+		order[race.Key()] = i
+	}
+	sort.SliceStable(merge.Conflicts, func(i, j int) bool {
+		if oi, oj := order[merge.Conflicts[i].Race], order[merge.Conflicts[j].Race]; oi != oj {
+			return oi < oj
+		}
+		return merge.Conflicts[i].Lane < merge.Conflicts[j].Lane
+	})
+	return merge
+}
+
+// mergeLanes carries captured lane results into race, which starts as a copy
+// of the revised race, recording conflicts for timed lanes that changed
+func (m *DrawMerge) mergeLanes(race *RaceData, captured RaceData, revised RaceData) {
+	lanes := make(map[int]bool)
+	for lane := range captured.Lanes {
+		lanes[lane] = true
+	}
+	for lane := range revised.Lanes {
+		lanes[lane] = true
+	}
+
+	for lane := range lanes {
+		capturedEntry, wasCaptured := captured.Lanes[lane]
+		revisedEntry, isRevised := revised.Lanes[lane]
+		timed := wasCaptured && capturedEntry.hasResult()
+
+		switch {
+		case timed && !isRevised:
+			race.Lanes[lane] = capturedEntry
+			m.addConflict(ConflictLaneRemoved, race.Key(), lane, &capturedEntry, nil,
+				"%s was timed but is not in the revised draw", capturedEntry.SchoolName)
+		case timed && revisedEntry.SchoolName != capturedEntry.SchoolName:
+			race.Lanes[lane] = capturedEntry
+			m.addConflict(ConflictSchoolChanged, race.Key(), lane, &capturedEntry, &revisedEntry,
+				"timed as %s, revised draw has %s", capturedEntry.SchoolName, revisedEntry.SchoolName)
+		case timed:
+			// Same crew, keep the revised info with the captured results
+			revisedEntry.Place = capturedEntry.Place
+			revisedEntry.Split = capturedEntry.Split
+			revisedEntry.Time = capturedEntry.Time
+			revisedEntry.Splits = capturedEntry.Splits
+			race.Lanes[lane] = revisedEntry
+		case !wasCaptured && isRevised && captured.hasResults():
+			delete(race.Lanes, lane)
+			m.addConflict(ConflictLaneAdded, race.Key(), lane, nil, &revisedEntry,
+				"%s was added to a race that has already been timed", revisedEntry.SchoolName)
+		}
+	}
+}
+
+func (m *DrawMerge) addConflict(kind ConflictKind, race RaceKey, lane int, captured *RaceEntry, revised *RaceEntry, format string, args ...any) {
+	m.Conflicts = append(m.Conflicts, MergeConflict{
+		Kind:     kind,
+		Race:     race,
+		Lane:     lane,
+		Captured: captured,
+		Revised:  revised,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// UseRevision resolves a conflict in favour of the revised draw, replacing
+// the captured lane (and its results) or dropping the captured race
+func (m *DrawMerge) UseRevision(conflict MergeConflict) {
+	for i := range m.Merged.Races {
+		race := &m.Merged.Races[i]
+		if race.Key() != conflict.Race {
+			continue
+		}
+
+		if conflict.Kind == ConflictRaceRemoved {
+			m.Merged.Races = append(m.Merged.Races[:i], m.Merged.Races[i+1:]...)
+			return
+		}
+
+		if conflict.Revised == nil {
+			delete(race.Lanes, conflict.Lane)
+		} else {
+			race.Lanes[conflict.Lane] = *conflict.Revised
+		}
+		// The race's results changed, so it needs approving again
+		race.Approved = false
+		race.Saved = false
+		return
+	}
+}
@@ -0,0 +1,243 @@
+package regattaClock
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// projectVersion is the version of the project file format written by WriteProject
+const projectVersion = 1
+
+// Entries in a project file
+const (
+	projectEntry  = "project.json"
+	workbookEntry = "workbook/" // Followed by the source workbook's file name
+)
+
+// Project is everything needed to carry on timing a regatta, on this laptop
+// or another: the imported regatta data with its approvals and saved status,
+// and the session events of every race and head race that has been timed
+type Project struct {
+	Regatta   *RegattaData
+	Sessions  []ProjectSession
+	HeadRaces []ProjectHeadRace
+}
+
+// ProjectSession is the timing session of a race in a project
+type ProjectSession struct {
+	Session    string         `json:"session,omitempty"` // Day or session of the race, if the draw has them
+	RaceNumber int            `json:"raceNumber"`
+	Open       bool           `json:"open"`   // Whether the race window was open when the project was saved
+	Events     []timing.Event `json:"events"` // The session's events, replayed when the race is timed again
+}
+
+// Key returns the key of the race the session timed
+func (s ProjectSession) Key() RaceKey {
+	return RaceKey{Session: s.Session, Number: s.RaceNumber}
+}
+
+// ProjectHeadRace is the timing session of a head race in a project, with
+// its captures and bow assignments
+type ProjectHeadRace struct {
+	Session string         `json:"session"` // Day or session of the draw timed as a head race
+	Open    bool           `json:"open"`    // Whether the head race window was open when the project was saved
+	Events  []timing.Event `json:"events"`  // The session's events, replayed when the head race is timed again
+}
+
+// projectFile is the JSON stored in a project file
+type projectFile struct {
+	Version   int               `json:"version"`
+	Workbook  string            `json:"workbook"` // File name of the bundled source workbook, if any
+	Regatta   *RegattaData      `json:"regatta"`
+	Sessions  []ProjectSession  `json:"sessions"`
+	HeadRaces []ProjectHeadRace `json:"headRaces,omitempty"`
+}
+
+// WriteProject saves a project to a zip file at path, bundling the regatta's
+// source workbook so the project can be opened on another laptop. The file
+// is replaced only once the new one has been written in full.
+func WriteProject(path string, project *Project) error {
+	if project == nil || project.Regatta == nil {
+		return fmt.Errorf("no regatta data to save")
+	}
+
+	sessions := make([]ProjectSession, len(project.Sessions))
+	copy(sessions, project.Sessions)
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Session != sessions[j].Session {
+			return sessions[i].Session < sessions[j].Session
+		}
+		return sessions[i].RaceNumber < sessions[j].RaceNumber
+	})
+	headRaces := make([]ProjectHeadRace, len(project.HeadRaces))
+	copy(headRaces, project.HeadRaces)
+	sort.Slice(headRaces, func(i, j int) bool {
+		return headRaces[i].Session < headRaces[j].Session
+	})
+	content := projectFile{
+		Version:   projectVersion,
+		Regatta:   project.Regatta,
+		Sessions:  sessions,
+		HeadRaces: headRaces,
+	}
+	if project.Regatta.FilePath != emptyString {
+		content.Workbook = filepath.Base(project.Regatta.FilePath)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create project file: %v", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if err := writeProjectZip(temp, content, project.Regatta.FilePath); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write project file: %v", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write project file: %v", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save project file: %v", err)
+	}
+	return nil
+}
+
+func writeProjectZip(w io.Writer, content projectFile, workbookPath string) error {
+	archive := zip.NewWriter(w)
+
+	entry, err := archive.Create(projectEntry)
+	if err != nil {
+		return fmt.Errorf("failed to write project file: %v", err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(content); err != nil {
+		return fmt.Errorf("failed to write project data: %v", err)
+	}
+
+	if content.Workbook != emptyString {
+		workbook, err := os.Open(workbookPath)
+		if err != nil {
+			return fmt.Errorf("failed to read source workbook: %v", err)
+		}
+		defer workbook.Close()
+
+		entry, err := archive.Create(workbookEntry + content.Workbook)
+		if err != nil {
+			return fmt.Errorf("failed to write project file: %v", err)
+		}
+		if _, err := io.Copy(entry, workbook); err != nil {
+			return fmt.Errorf("failed to bundle source workbook: %v", err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write project file: %v", err)
+	}
+	return nil
+}
+
+// ReadProject opens a project file, extracting its source workbook into
+// workbookDir so results can be saved back into it
+func ReadProject(path string, workbookDir string) (*Project, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open project file: %v", err)
+	}
+	defer archive.Close()
+
+	var content projectFile
+	found := false
+	for _, file := range archive.File {
+		if file.Name != projectEntry {
+			continue
+		}
+		if err := readProjectJSON(file, &content); err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a regatta project file", filepath.Base(path))
+	}
+	if content.Version > projectVersion {
+		return nil, fmt.Errorf("project file version %d is newer than this version of Regatta Clock supports", content.Version)
+	}
+	if content.Regatta == nil {
+		return nil, fmt.Errorf("project file has no regatta data")
+	}
+
+	content.Regatta.FilePath = emptyString
+	if content.Workbook != emptyString {
+		workbookPath, err := extractWorkbook(archive, content.Workbook, workbookDir)
+		if err != nil {
+			return nil, err
+		}
+		content.Regatta.FilePath = workbookPath
+	}
+
+	return &Project{Regatta: content.Regatta, Sessions: content.Sessions, HeadRaces: content.HeadRaces}, nil
+}
+
+func readProjectJSON(file *zip.File, content *projectFile) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read project data: %v", err)
+	}
+	defer reader.Close()
+	if err := json.NewDecoder(reader).Decode(content); err != nil {
+		return fmt.Errorf("failed to read project data: %v", err)
+	}
+	return nil
+}
+
+// extractWorkbook writes the bundled workbook into dir and returns its path
+func extractWorkbook(archive *zip.ReadCloser, name string, dir string) (string, error) {
+	// The name comes from the file, so keep it to a plain file name
+	name = filepath.Base(name)
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return emptyString, fmt.Errorf("invalid workbook name %q in project file", name)
+	}
+
+	for _, file := range archive.File {
+		if file.Name != workbookEntry+name {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return emptyString, fmt.Errorf("failed to read bundled workbook: %v", err)
+		}
+		defer reader.Close()
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return emptyString, fmt.Errorf("failed to create workbook directory: %v", err)
+		}
+		path := filepath.Join(dir, name)
+		workbook, err := os.Create(path)
+		if err != nil {
+			return emptyString, fmt.Errorf("failed to extract bundled workbook: %v", err)
+		}
+		if _, err := io.Copy(workbook, reader); err != nil {
+			workbook.Close()
+			return emptyString, fmt.Errorf("failed to extract bundled workbook: %v", err)
+		}
+		if err := workbook.Close(); err != nil {
+			return emptyString, fmt.Errorf("failed to extract bundled workbook: %v", err)
+		}
+		return path, nil
+	}
+	return emptyString, fmt.Errorf("project file is missing its workbook %s", name)
+}
@@ -0,0 +1,199 @@
+package regattaClock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// projectExtension is the file extension of regatta project files
+const projectExtension = ".regatta"
+
+// openProject asks for a project file and picks the regatta up from it,
+// reopening the race windows that were open when it was saved
+func (a *App) openProject() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if reader == nil {
+			// User cancelled
+			return
+		}
+		reader.Close()
+
+		path := reader.URI().Path()
+		if !a.timingWindowsOpen() {
+			a.loadProject(path)
+			return
+		}
+		dialog.ShowConfirm(
+			"Open Project",
+			"Opening a project closes the open race and head race windows. Continue?",
+			func(confirmed bool) {
+				if confirmed {
+					a.loadProject(path)
+				}
+			},
+			a.window,
+		)
+	}, a.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{projectExtension}))
+	openDialog.Show()
+}
+
+// loadProject reads a project file and replaces the loaded regatta with it
+func (a *App) loadProject(path string) {
+	// Results are saved into a copy of the bundled workbook kept with the app
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	workbookDir := filepath.Join(a.app.Storage().RootURI().Path(), "projects", name)
+	project, err := ReadProject(path, workbookDir)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+
+	a.closeTimingWindows()
+	a.raceLogs = make(map[RaceKey]*timing.EventLog)
+	for _, session := range project.Sessions {
+		a.raceLogs[session.Key()] = timing.NewEventLog(session.Events, nil)
+	}
+	a.headRaceLogs = make(map[string]*timing.EventLog)
+	for _, headRace := range project.HeadRaces {
+		a.headRaceLogs[headRace.Session] = timing.NewEventLog(headRace.Events, nil)
+	}
+	a.projectPath = path
+	a.setRegattaData(project.Regatta)
+
+	// Carry on timing the races that were open
+	for _, session := range project.Sessions {
+		if !session.Open {
+			continue
+		}
+		for _, race := range project.Regatta.Races {
+			if race.Key() == session.Key() {
+				a.openRaceClock(race)
+				break
+			}
+		}
+	}
+	for _, headRace := range project.HeadRaces {
+		if headRace.Open {
+			a.openHeadRaceSession(headRace.Session)
+		}
+	}
+	a.offerRecovery()
+	a.window.RequestFocus()
+}
+
+// saveProject saves the regatta to the project file it was opened from or
+// last saved to, asking for a file the first time
+func (a *App) saveProject() {
+	if a.projectPath == emptyString {
+		a.saveProjectAs()
+		return
+	}
+	a.writeProject(a.projectPath)
+}
+
+// saveProjectAs asks for a project file to save the regatta to
+func (a *App) saveProjectAs() {
+	if a.regattaData == nil {
+		dialog.ShowInformation("Save Project", "Import a regatta table before saving a project.", a.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if writer == nil {
+			// User cancelled
+			return
+		}
+		// The project is written to a temporary file and renamed into place
+		writer.Close()
+
+		path := writer.URI().Path()
+		if filepath.Ext(path) != projectExtension {
+			// Don't leave the empty file the dialog created behind
+			os.Remove(path)
+			path += projectExtension
+		}
+		a.writeProject(path)
+	}, a.window)
+	name := "regatta"
+	if a.regattaData.RegattaName != emptyString {
+		name = a.regattaData.RegattaName
+	}
+	saveDialog.SetFileName(name + projectExtension)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{projectExtension}))
+	saveDialog.Show()
+}
+
+// writeProject saves the regatta data and the session of every race timed
+// so far to a project file
+func (a *App) writeProject(path string) {
+	if a.regattaData == nil {
+		dialog.ShowInformation("Save Project", "Import a regatta table before saving a project.", a.window)
+		return
+	}
+
+	project := &Project{Regatta: a.regattaData}
+	for key, log := range a.raceLogs {
+		_, open := a.raceWindows[key]
+		project.Sessions = append(project.Sessions, ProjectSession{
+			Session:    key.Session,
+			RaceNumber: key.Number,
+			Open:       open,
+			Events:     log.Events(),
+		})
+	}
+	for session, log := range a.headRaceLogs {
+		_, open := a.headRaceWindows[session]
+		project.HeadRaces = append(project.HeadRaces, ProjectHeadRace{
+			Session: session,
+			Open:    open,
+			Events:  log.Events(),
+		})
+	}
+
+	if err := WriteProject(path, project); err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.projectPath = path
+	dialog.ShowInformation("Save Project", fmt.Sprintf("Saved project %s", filepath.Base(path)), a.window)
+}
+
+// timingWindowsOpen reports whether any race or head race window is open
+func (a *App) timingWindowsOpen() bool {
+	return len(a.raceWindows) > 0 || len(a.headRaceWindows) > 0
+}
+
+// closeTimingWindows closes every race and head race window, each finishing
+// its journal as it closes
+func (a *App) closeTimingWindows() {
+	raceApps := make([]*App, 0, len(a.raceWindows))
+	for _, raceApp := range a.raceWindows {
+		raceApps = append(raceApps, raceApp)
+	}
+	for _, raceApp := range raceApps {
+		raceApp.window.Close()
+	}
+	headRaces := make([]*headRaceWindow, 0, len(a.headRaceWindows))
+	for _, h := range a.headRaceWindows {
+		headRaces = append(headRaces, h)
+	}
+	for _, h := range headRaces {
+		h.window.Close()
+	}
+}
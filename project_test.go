@@ -0,0 +1,240 @@
+package regattaClock
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// exampleWorkbook is the sample draw bundled in the tests' projects
+const exampleWorkbook = "Example Regatta Input Table.xlsx"
+
+// projectRegatta is a regatta with a Saturday race timed and saved
+func projectRegatta() *RegattaData {
+	return &RegattaData{
+		RegattaName: "Spring Sprints",
+		Date:        time.Date(2025, time.April, 5, 0, 0, 0, 0, time.UTC),
+		LaneCount:   6,
+		Races: []RaceData{
+			{
+				RaceNumber: 1,
+				Session:    "Saturday",
+				Lanes:      map[int]RaceEntry{1: {SchoolName: "Alpha", Place: "1", Time: "07:01.2", Bow: 3}},
+				Saved:      true,
+			},
+			{RaceNumber: 1, Session: "Sunday", Lanes: map[int]RaceEntry{2: {SchoolName: "Bravo"}}},
+		},
+	}
+}
+
+func TestProjectRoundTrip(t *testing.T) {
+	startedAt := time.Date(2025, time.April, 5, 9, 30, 0, 0, time.UTC)
+	raceEvents := []timing.Event{
+		{Kind: timing.EventOpen, At: startedAt, LaneCount: 6},
+		{Kind: timing.EventStart, At: startedAt},
+		{Kind: timing.EventCapture, At: startedAt.Add(421 * time.Second), Time: timing.Duration(421 * time.Second)},
+		{Kind: timing.EventAssignLane, At: startedAt.Add(430 * time.Second), Lane: 1},
+	}
+	headEvents := []timing.Event{
+		{Kind: timing.EventOpen, At: startedAt, Head: true},
+		{Kind: timing.EventAddCrew, At: startedAt, Crew: &timing.Crew{Bow: 3, Event: "Men's 8+", Name: "Alpha"}},
+		{Kind: timing.EventRenumberCrew, At: startedAt, Bow: 3, NewBow: 4},
+	}
+
+	tests := []struct {
+		name          string
+		workbook      bool // Whether the regatta was read from the example workbook
+		sessions      []ProjectSession
+		headRaces     []ProjectHeadRace
+		wantSessions  []ProjectSession
+		wantHeadRaces []ProjectHeadRace
+	}{
+		{
+			name:         "no sessions",
+			wantSessions: []ProjectSession{},
+		},
+		{
+			name: "sessions sorted by day and race",
+			sessions: []ProjectSession{
+				{Session: "Sunday", RaceNumber: 1, Events: raceEvents},
+				{Session: "Saturday", RaceNumber: 2, Events: raceEvents},
+				{Session: "Saturday", RaceNumber: 1, Open: true, Events: raceEvents},
+			},
+			wantSessions: []ProjectSession{
+				{Session: "Saturday", RaceNumber: 1, Open: true, Events: raceEvents},
+				{Session: "Saturday", RaceNumber: 2, Events: raceEvents},
+				{Session: "Sunday", RaceNumber: 1, Events: raceEvents},
+			},
+		},
+		{
+			name: "head races sorted by day",
+			headRaces: []ProjectHeadRace{
+				{Session: "Sunday", Events: headEvents},
+				{Session: "Saturday", Open: true, Events: headEvents},
+			},
+			wantSessions: []ProjectSession{},
+			wantHeadRaces: []ProjectHeadRace{
+				{Session: "Saturday", Open: true, Events: headEvents},
+				{Session: "Sunday", Events: headEvents},
+			},
+		},
+		{
+			name:     "bundled workbook",
+			workbook: true,
+			sessions: []ProjectSession{{Session: "Saturday", RaceNumber: 1, Events: raceEvents}},
+			wantSessions: []ProjectSession{
+				{Session: "Saturday", RaceNumber: 1, Events: raceEvents},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			regatta := projectRegatta()
+			if tt.workbook {
+				regatta.FilePath = filepath.Join("testdata", exampleWorkbook)
+			}
+			path := filepath.Join(dir, "regatta"+projectExtension)
+			project := &Project{Regatta: regatta, Sessions: tt.sessions, HeadRaces: tt.headRaces}
+			if err := WriteProject(path, project); err != nil {
+				t.Fatalf("WriteProject() error = %v", err)
+			}
+
+			workbookDir := filepath.Join(dir, "workbooks")
+			got, err := ReadProject(path, workbookDir)
+			if err != nil {
+				t.Fatalf("ReadProject() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Sessions, tt.wantSessions) {
+				t.Errorf("Sessions = %+v, want %+v", got.Sessions, tt.wantSessions)
+			}
+			if !reflect.DeepEqual(got.HeadRaces, tt.wantHeadRaces) {
+				t.Errorf("HeadRaces = %+v, want %+v", got.HeadRaces, tt.wantHeadRaces)
+			}
+
+			want := projectRegatta()
+			if got.Regatta.RegattaName != want.RegattaName || !got.Regatta.Date.Equal(want.Date) ||
+				got.Regatta.LaneCount != want.LaneCount {
+				t.Errorf("Regatta = %+v, want %+v", got.Regatta, want)
+			}
+			if len(got.Regatta.Races) != len(want.Races) {
+				t.Fatalf("%d races, want %d", len(got.Regatta.Races), len(want.Races))
+			}
+			for i, race := range got.Regatta.Races {
+				if race.Key() != want.Races[i].Key() || race.Saved != want.Races[i].Saved ||
+					!reflect.DeepEqual(race.Lanes, want.Races[i].Lanes) {
+					t.Errorf("race %d = %+v, want %+v", i+1, race, want.Races[i])
+				}
+			}
+
+			if !tt.workbook {
+				if got.Regatta.FilePath != emptyString {
+					t.Errorf("FilePath = %q, want none", got.Regatta.FilePath)
+				}
+				return
+			}
+			if wantPath := filepath.Join(workbookDir, exampleWorkbook); got.Regatta.FilePath != wantPath {
+				t.Errorf("FilePath = %q, want %q", got.Regatta.FilePath, wantPath)
+			}
+			extracted, err := os.ReadFile(got.Regatta.FilePath)
+			if err != nil {
+				t.Fatalf("failed to read extracted workbook: %v", err)
+			}
+			original, err := os.ReadFile(filepath.Join("testdata", exampleWorkbook))
+			if err != nil {
+				t.Fatalf("failed to read example workbook: %v", err)
+			}
+			if !bytes.Equal(extracted, original) {
+				t.Error("extracted workbook differs from the original")
+			}
+		})
+	}
+}
+
+func TestWriteProjectErrors(t *testing.T) {
+	missing := projectRegatta()
+	missing.FilePath = filepath.Join(t.TempDir(), "missing.xlsx")
+
+	tests := []struct {
+		name    string
+		project *Project
+	}{
+		{name: "no project"},
+		{name: "no regatta", project: &Project{}},
+		{name: "workbook missing", project: &Project{Regatta: missing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "regatta"+projectExtension)
+			if err := WriteProject(path, tt.project); err == nil {
+				t.Fatal("WriteProject() succeeded, want an error")
+			}
+			// A failed save leaves nothing behind
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("files left after a failed save: %v", entries)
+			}
+		})
+	}
+}
+
+func TestReadProjectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string // Zip entries and their content, nil for a file that is not a zip
+	}{
+		{name: "not a zip file"},
+		{name: "no project data", entries: map[string]string{"readme.txt": "hello"}},
+		{name: "invalid project data", entries: map[string]string{projectEntry: "{"}},
+		{name: "newer version", entries: map[string]string{projectEntry: `{"version": 999, "regatta": {}}`}},
+		{name: "no regatta", entries: map[string]string{projectEntry: `{"version": 1}`}},
+		{
+			name:    "workbook missing",
+			entries: map[string]string{projectEntry: `{"version": 1, "workbook": "draw.xlsx", "regatta": {}}`},
+		},
+		{
+			name:    "workbook outside the directory",
+			entries: map[string]string{projectEntry: `{"version": 1, "workbook": "..", "regatta": {}}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "regatta"+projectExtension)
+			var content bytes.Buffer
+			if tt.entries == nil {
+				content.WriteString("not a project")
+			} else {
+				archive := zip.NewWriter(&content)
+				for name, data := range tt.entries {
+					entry, err := archive.Create(name)
+					if err != nil {
+						t.Fatalf("failed to create zip entry: %v", err)
+					}
+					if _, err := entry.Write([]byte(data)); err != nil {
+						t.Fatalf("failed to write zip entry: %v", err)
+					}
+				}
+				if err := archive.Close(); err != nil {
+					t.Fatalf("failed to write zip file: %v", err)
+				}
+			}
+			if err := os.WriteFile(path, content.Bytes(), 0o644); err != nil {
+				t.Fatalf("failed to write project file: %v", err)
+			}
+
+			if _, err := ReadProject(path, filepath.Join(dir, "workbooks")); err == nil {
+				t.Error("ReadProject() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package regattaClock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// Protest is a protest or correction that reopened an approved race for
+// review. The race stays under review until the referee approves it again.
+type Protest struct {
+	Reason  string
+	Opened  time.Time
+	Closed  time.Time // Zero while the race is under review
+	Outcome string    // Referee's note when the race was approved again
+}
+
+// OpenProtest returns the protest the race is under review for, or nil
+func (r RaceData) OpenProtest() *Protest {
+	if len(r.Protests) == 0 || !r.Protests[len(r.Protests)-1].Closed.IsZero() {
+		return nil
+	}
+	return &r.Protests[len(r.Protests)-1]
+}
+
+// ResultsVersion returns the version of the race's approved results: 1 once
+// first approved, going up each time an approval changes them, or 0 if the
+// race has never been approved. Every version is kept with its approval.
+func (r RaceData) ResultsVersion() int {
+	version := 0
+	for i, approval := range r.Approvals {
+		if i == 0 || len(approval.Changes) > 0 {
+			version++
+		}
+	}
+	return version
+}
+
+// Amended reports whether the race's approved results have been changed
+// since they were first approved
+func (r RaceData) Amended() bool {
+	return r.ResultsVersion() > 1
+}
+
+// VersionLabel labels published results with their version, such as
+// "Results version 2 - AMENDED"
+func (r RaceData) VersionLabel() string {
+	if r.Amended() {
+		return fmt.Sprintf("Results version %d - AMENDED", r.ResultsVersion())
+	}
+	return fmt.Sprintf("Results version %d", r.ResultsVersion())
+}
+
+// showProtest asks for the reason an approved race is being reopened
+func (a *App) showProtest(key RaceKey) {
+	reasonEntry := widget.NewEntry()
+	reasonEntry.SetPlaceHolder("Protest or correction")
+	dialog.ShowForm(
+		fmt.Sprintf("Protest %s", key.Label()),
+		"Reopen",
+		"Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Reason", reasonEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := a.openProtest(key, reasonEntry.Text); err != nil {
+				dialog.ShowError(err, a.window)
+			}
+		},
+		a.window,
+	)
+}
+
+// openProtest reopens an approved race for review. Its results can be
+// edited in the race window and need approving again, which closes the
+// protest and publishes a new version if they changed.
+func (a *App) openProtest(key RaceKey, reason string) error {
+	race := a.findRace(key)
+	if race == nil {
+		return fmt.Errorf("%s not found in regatta data", key)
+	}
+	if !race.Approved {
+		return fmt.Errorf("only approved races can be protested")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == emptyString {
+		return fmt.Errorf("enter the reason for the protest")
+	}
+
+	// Protests are timed on the race session's clock when its window is open
+	opened := time.Now()
+	if raceApp, open := a.raceWindows[key]; open {
+		opened = raceApp.session.Now()
+	}
+	protest := Protest{Reason: reason, Opened: opened}
+	meta, err := json.Marshal(protest)
+	if err != nil {
+		return fmt.Errorf("failed to record protest: %v", err)
+	}
+	race.Protests = append(race.Protests, protest)
+	race.Approved = false
+
+	if log, timed := a.raceLogs[key]; timed {
+		log.Record(timing.Event{Kind: timing.EventProtested, At: protest.Opened, Meta: meta})
+	}
+	if raceApp, open := a.raceWindows[key]; open {
+		raceApp.amended = false
+		raceApp.disableApprovedActions()
+		raceApp.updateRefereeButton()
+	}
+	a.updateRaceTree()
+	return nil
+}
+
+// closeProtest closes the protest a race is under review for, if any, when
+// the referee approves its results again
+func closeProtest(race *RaceData, approval Approval) {
+	if protest := race.OpenProtest(); protest != nil {
+		protest.Closed = approval.At
+		protest.Outcome = approval.Note
+	}
+}
+
+// protestFromEvent reads the protest recorded with a protested event
+func protestFromEvent(event timing.Event) Protest {
+	var protest Protest
+	if err := json.Unmarshal(event.Meta, &protest); err != nil {
+		// The race stays under review even if the reason cannot be read
+		protest = Protest{Reason: "unreadable protest record"}
+	}
+	if protest.Opened.IsZero() {
+		protest.Opened = event.At
+	}
+	return protest
+}
@@ -0,0 +1,167 @@
+package regattaClock
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	keepCaptured = "Keep timed result"
+	useRevised   = "Use revised draw"
+)
+
+// reloadDraw merges a revised draw into the loaded regatta, keeping captured
+// results and asking how to resolve conflicts
+func (a *App) reloadDraw() {
+	if a.regattaData == nil {
+		// Nothing captured yet, so a plain import will do
+		a.loadExcel(false)
+		return
+	}
+
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if reader == nil {
+			// User cancelled
+			return
+		}
+		defer reader.Close()
+
+		// Verify file extension
+		uri := reader.URI()
+		if ext := strings.ToLower(uri.Extension()); ext != ".xlsx" && ext != ".csv" {
+			dialog.ShowError(fmt.Errorf("only .xlsx and .csv files are supported"), a.window)
+			return
+		}
+
+		filePath := uri.Path()
+		a.withImportOptions(filePath, func(opts ImportOptions) {
+			revised, err := ReadDrawFile(filePath, opts)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+
+			merge := MergeDraw(a.timedRegatta(), revised)
+			if len(merge.Conflicts) == 0 {
+				a.applyMerge(merge)
+				return
+			}
+			a.showMergeConflicts(merge)
+		})
+	}, a.window)
+}
+
+// timedRegatta returns a copy of the regatta data with the results shown in
+// open race windows, so lanes that were timed but not yet approved or saved
+// are merged like those that were
+func (a *App) timedRegatta() *RegattaData {
+	timed := *a.regattaData
+	timed.Races = make([]RaceData, len(a.regattaData.Races))
+	for i, race := range a.regattaData.Races {
+		if raceApp, open := a.raceWindows[race.Key()]; open && !race.Approved {
+			race = raceApp.resultsRace(race)
+		}
+		timed.Races[i] = race
+	}
+	return &timed
+}
+
+// untimedResults takes the unapproved results of open race windows back out
+// of the merged draw; they stay in the windows until approved or saved
+func (a *App) untimedResults(merged *RegattaData) {
+	for i := range merged.Races {
+		race := &merged.Races[i]
+		if _, open := a.raceWindows[race.Key()]; !open {
+			continue
+		}
+		current := a.findRace(race.Key())
+		if current != nil && current.Approved {
+			continue
+		}
+		for lane, entry := range race.Lanes {
+			stored := RaceEntry{}
+			if current != nil {
+				stored = current.Lanes[lane]
+			}
+			entry.Place = stored.Place
+			entry.Split = stored.Split
+			entry.Time = stored.Time
+			entry.Splits = stored.Splits
+			race.Lanes[lane] = entry
+		}
+	}
+}
+
+// applyMerge replaces the regatta data with the merged draw
+func (a *App) applyMerge(merge *DrawMerge) {
+	a.untimedResults(merge.Merged)
+	a.setRegattaData(merge.Merged)
+
+	if len(merge.Merged.Diagnostics) > 0 {
+		a.showImportDiagnostics(merge.Merged.Diagnostics)
+		return
+	}
+	dialog.ShowInformation("Reload Draw", "Revised draw merged; captured results were kept", a.window)
+}
+
+// showMergeConflicts asks, for each conflict, whether to keep the timed
+// result or take the revised draw
+func (a *App) showMergeConflicts(merge *DrawMerge) {
+	choices := make([]*widget.RadioGroup, len(merge.Conflicts))
+	rows := container.NewVBox()
+	for i, conflict := range merge.Conflicts {
+		heading := conflict.Race.Label()
+		if conflict.Lane > 0 {
+			heading = fmt.Sprintf("%s, Lane %d", heading, conflict.Lane)
+		}
+		headingLabel := widget.NewLabel(fmt.Sprintf("%s - %s", heading, conflict.Kind))
+		headingLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+		choice := widget.NewRadioGroup([]string{keepCaptured, useRevised}, nil)
+		choice.Horizontal = true
+		choice.Required = true
+		choice.SetSelected(keepCaptured)
+		choices[i] = choice
+
+		rows.Add(headingLabel)
+		rows.Add(widget.NewLabel(conflict.Message))
+		rows.Add(choice)
+		rows.Add(widget.NewSeparator())
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(600, 400))
+	content := container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("The revised draw changes %d race(s) or lane(s) that were already timed.", len(merge.Conflicts))),
+		nil, nil, nil,
+		scroll,
+	)
+
+	dialog.ShowCustomConfirm(
+		"Resolve Draw Conflicts",
+		"Apply",
+		"Cancel",
+		content,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			for i, conflict := range merge.Conflicts {
+				if choices[i].Selected == useRevised {
+					merge.UseRevision(conflict)
+				}
+			}
+			a.applyMerge(merge)
+		},
+		a.window,
+	)
+}
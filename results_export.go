@@ -0,0 +1,201 @@
+package regattaClock
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportFormat identifies an output format for ExportResults
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+// ResultRow is a single lane's result in an exported results file
+type ResultRow struct {
+	RaceNumber     int    `json:"raceNumber"`
+	Session        string `json:"session"`
+	BoatClass      string `json:"boatClass"`
+	Flight         string `json:"flight"`
+	Lane           int    `json:"lane"`
+	School         string `json:"school"`
+	AdditionalInfo string `json:"additionalInfo"`
+	Place          string `json:"place"`
+	Split          string `json:"split"`
+	Time           string `json:"time"`
+	Version        int    `json:"version"` // Approved results version, 0 if not yet approved
+	Amended        bool   `json:"amended"` // Whether the results were changed after they were first approved
+
+	Splits []SplitTime `json:"splits,omitempty"` // Time and segment at each split station timed
+}
+
+// resultsHeader is the CSV header row, in ResultRow field order. A time and a
+// segment column for each split station timed follow it.
+var resultsHeader = []string{
+	"Race", "Session", "Boat Class", "Flight", "Lane", "School", "Additional Info", "Place", "Split", "Time",
+	"Version", "Amended",
+}
+
+// ParseExportFormat returns the export format for a name or file extension
+// such as "csv" or ".json"
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(strings.TrimPrefix(name, "."))) {
+	case ExportCSV:
+		return ExportCSV, nil
+	case ExportJSON:
+		return ExportJSON, nil
+	}
+	return emptyString, fmt.Errorf("unsupported export format %q", name)
+}
+
+// ResultRows flattens the regatta data into one row per scheduled lane,
+// ordered by session, race number and then lane
+func ResultRows(data *RegattaData) []ResultRow {
+	rows := make([]ResultRow, 0)
+	if data == nil {
+		return rows
+	}
+
+	races := make([]RaceData, len(data.Races))
+	copy(races, data.Races)
+	sortRaces(races)
+
+	for _, race := range races {
+		lanes := make([]int, 0, len(race.Lanes))
+		for lane := range race.Lanes {
+			lanes = append(lanes, lane)
+		}
+		sort.Ints(lanes)
+
+		for _, lane := range lanes {
+			entry := race.Lanes[lane]
+			rows = append(rows, ResultRow{
+				RaceNumber:     race.RaceNumber,
+				Session:        race.Session,
+				BoatClass:      race.BoatClass(),
+				Flight:         race.Flight(),
+				Lane:           lane,
+				School:         entry.SchoolName,
+				AdditionalInfo: entry.AdditionalInfo,
+				Place:          entry.Place,
+				Split:          entry.Split,
+				Time:           entry.Time,
+				Version:        race.ResultsVersion(),
+				Amended:        race.Amended(),
+				Splits:         entry.Splits,
+			})
+		}
+	}
+	return rows
+}
+
+// ExportResults writes the results of every race in the regatta to w in the
+// given format
+func ExportResults(w io.Writer, data *RegattaData, format ExportFormat) error {
+	rows := ResultRows(data)
+
+	switch format {
+	case ExportCSV:
+		return writeResultsCSV(w, rows)
+	case ExportJSON:
+		return writeResultsJSON(w, data, rows)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// splitStations returns the distance of every split station timed in rows,
+// nearest the start first
+func splitStations(rows []ResultRow) []int {
+	timed := make(map[int]bool)
+	for _, row := range rows {
+		for _, split := range row.Splits {
+			timed[split.Distance] = true
+		}
+	}
+	distances := make([]int, 0, len(timed))
+	for distance := range timed {
+		distances = append(distances, distance)
+	}
+	sort.Ints(distances)
+	return distances
+}
+
+func writeResultsCSV(w io.Writer, rows []ResultRow) error {
+	stations := splitStations(rows)
+	header := append([]string{}, resultsHeader...)
+	for _, distance := range stations {
+		header = append(header, fmt.Sprintf("%dm", distance), fmt.Sprintf("%dm Segment", distance))
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, row := range rows {
+		record := []string{
+			strconv.Itoa(row.RaceNumber),
+			row.Session,
+			row.BoatClass,
+			row.Flight,
+			strconv.Itoa(row.Lane),
+			row.School,
+			row.AdditionalInfo,
+			row.Place,
+			row.Split,
+			row.Time,
+			emptyString,
+			emptyString,
+		}
+		if row.Version > 0 {
+			record[10] = strconv.Itoa(row.Version)
+		}
+		if row.Amended {
+			record[11] = "Amended"
+		}
+		for _, distance := range stations {
+			split, _ := splitAt(row.Splits, distance)
+			record = append(record, split.Time, split.Segment)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %v", err)
+	}
+	return nil
+}
+
+func writeResultsJSON(w io.Writer, data *RegattaData, rows []ResultRow) error {
+	export := struct {
+		RegattaName string      `json:"regattaName"`
+		Date        string      `json:"date"`
+		Results     []ResultRow `json:"results"`
+	}{
+		Results: rows,
+	}
+	if data != nil {
+		export.RegattaName = data.RegattaName
+		export.Date = data.DateText
+		if !data.Date.IsZero() {
+			export.Date = data.Date.Format("2006-01-02")
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to write JSON: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package regattaClock
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportRegatta has a Sunday race drawn between two out of order Saturday
+// races, one timed at a 500 m split station
+func exportRegatta() *RegattaData {
+	return &RegattaData{
+		RegattaName: "Spring Sprints",
+		Date:        time.Date(2025, time.April, 5, 0, 0, 0, 0, time.UTC),
+		Races: []RaceData{
+			{
+				RaceNumber: 2,
+				Session:    "Saturday",
+				RawData:    [][]string{{"M2x"}, {"Heat 2"}},
+				Lanes:      map[int]RaceEntry{3: {SchoolName: "Charlie", Place: "1", Time: "06:40.0"}},
+			},
+			{
+				RaceNumber: 1,
+				Session:    "Sunday",
+				RawData:    [][]string{{"W1x"}, {"Final"}},
+				Lanes:      map[int]RaceEntry{1: {SchoolName: "Delta"}},
+			},
+			{
+				RaceNumber: 1,
+				Session:    "Saturday",
+				RawData:    [][]string{{"M1x"}, {"Heat 1"}},
+				Lanes: map[int]RaceEntry{
+					2: {SchoolName: "Bravo", AdditionalInfo: "B", Place: "2", Split: "1.5", Time: "07:05.0"},
+					1: {
+						SchoolName: "Alpha", AdditionalInfo: "A", Place: "1", Time: "07:01.2",
+						Splits: []SplitTime{{Distance: 500, Time: "01:45.0"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ExportFormat
+		wantErr bool
+	}{
+		{name: "csv", want: ExportCSV},
+		{name: ".CSV", want: ExportCSV},
+		{name: "json", want: ExportJSON},
+		{name: ".json", want: ExportJSON},
+		{name: "xlsx", wantErr: true},
+		{name: emptyString, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExportFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExportFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseExportFormat(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   *RegattaData
+		format ExportFormat
+		want   string
+	}{
+		{
+			name:   "csv",
+			data:   exportRegatta(),
+			format: ExportCSV,
+			want: "Race,Session,Boat Class,Flight,Lane,School,Additional Info,Place,Split,Time,Version,Amended,500m,500m Segment\n" +
+				"1,Saturday,M1x,Heat 1,1,Alpha,A,1,,07:01.2,,,01:45.0,\n" +
+				"1,Saturday,M1x,Heat 1,2,Bravo,B,2,1.5,07:05.0,,,,\n" +
+				"2,Saturday,M2x,Heat 2,3,Charlie,,1,,06:40.0,,,,\n" +
+				"1,Sunday,W1x,Final,1,Delta,,,,,,,,\n",
+		},
+		{
+			name:   "csv with no races",
+			data:   &RegattaData{},
+			format: ExportCSV,
+			want:   "Race,Session,Boat Class,Flight,Lane,School,Additional Info,Place,Split,Time,Version,Amended\n",
+		},
+		{
+			name:   "json",
+			data:   exportRegatta(),
+			format: ExportJSON,
+			want: `{
+  "regattaName": "Spring Sprints",
+  "date": "2025-04-05",
+  "results": [
+    {"raceNumber": 1, "session": "Saturday", "boatClass": "M1x", "flight": "Heat 1", "lane": 1,
+     "school": "Alpha", "additionalInfo": "A", "place": "1", "split": "", "time": "07:01.2",
+     "version": 0, "amended": false, "splits": [{"distance": 500, "time": "01:45.0"}]},
+    {"raceNumber": 1, "session": "Saturday", "boatClass": "M1x", "flight": "Heat 1", "lane": 2,
+     "school": "Bravo", "additionalInfo": "B", "place": "2", "split": "1.5", "time": "07:05.0",
+     "version": 0, "amended": false},
+    {"raceNumber": 2, "session": "Saturday", "boatClass": "M2x", "flight": "Heat 2", "lane": 3,
+     "school": "Charlie", "additionalInfo": "", "place": "1", "split": "", "time": "06:40.0",
+     "version": 0, "amended": false},
+    {"raceNumber": 1, "session": "Sunday", "boatClass": "W1x", "flight": "Final", "lane": 1,
+     "school": "Delta", "additionalInfo": "", "place": "", "split": "", "time": "",
+     "version": 0, "amended": false}
+  ]
+}`,
+		},
+		{
+			name:   "json with an unparsed date",
+			data:   &RegattaData{RegattaName: "Spring Sprints", DateText: "Easter Saturday"},
+			format: ExportJSON,
+			want:   `{"regattaName": "Spring Sprints", "date": "Easter Saturday", "results": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := ExportResults(&out, tt.data, tt.format); err != nil {
+				t.Fatalf("ExportResults() error = %v", err)
+			}
+			if tt.format == ExportCSV {
+				if got := out.String(); got != tt.want {
+					t.Errorf("ExportResults() =\n%s\nwant\n%s", got, tt.want)
+				}
+				return
+			}
+
+			var got, want any
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("ExportResults() wrote invalid JSON: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid expected JSON: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ExportResults() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExportResultsUnsupportedFormat(t *testing.T) {
+	err := ExportResults(&bytes.Buffer{}, exportRegatta(), ExportFormat("xlsx"))
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format") {
+		t.Errorf("ExportResults() error = %v, want unsupported export format", err)
+	}
+}
@@ -0,0 +1,125 @@
+package regattaClock
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// resultSheetWidths are the PDF column widths in millimetres, in
+// resultSheetHeaders order
+var resultSheetWidths = []float64{25, 25, 30, 30, 80}
+
+// WriteRacePDF writes a printable results sheet for a single race to w
+func WriteRacePDF(w io.Writer, data *RegattaData, race RaceData) error {
+	pdf := newResultsPDF(data)
+	addRacePage(pdf, race)
+	return outputPDF(w, pdf)
+}
+
+// WriteRegattaPDF writes a results booklet with one page per approved race to w
+func WriteRegattaPDF(w io.Writer, data *RegattaData) error {
+	if data == nil {
+		return fmt.Errorf("no regatta data to print")
+	}
+
+	races := make([]RaceData, 0)
+	for _, race := range data.Races {
+		if race.Approved {
+			races = append(races, race)
+		}
+	}
+	if len(races) == 0 {
+		return fmt.Errorf("no approved races to print")
+	}
+	sortRaces(races)
+
+	pdf := newResultsPDF(data)
+	for _, race := range races {
+		addRacePage(pdf, race)
+	}
+	return outputPDF(w, pdf)
+}
+
+// newResultsPDF creates a portrait A4 document with the regatta name and date
+// in every page header
+func newResultsPDF(data *RegattaData) *fpdf.Fpdf {
+	pdf := fpdf.New("P", "mm", "A4", emptyString)
+	tr := pdf.UnicodeTranslatorFromDescriptor(emptyString)
+
+	regattaName, regattaDate := emptyString, emptyString
+	if data != nil {
+		regattaName = data.RegattaName
+		regattaDate = data.DisplayDate()
+	}
+	pdf.SetTitle(regattaName, true)
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(0, 8, tr(regattaName), emptyString, 1, "C", false, 0, emptyString)
+		pdf.SetFont("Helvetica", emptyString, 11)
+		pdf.CellFormat(0, 6, tr(regattaDate), emptyString, 1, "C", false, 0, emptyString)
+		pdf.Ln(4)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()), emptyString, 0, "C", false, 0, emptyString)
+	})
+	return pdf
+}
+
+// addRacePage adds a page with the race title and its results table
+func addRacePage(pdf *fpdf.Fpdf, race RaceData) {
+	tr := pdf.UnicodeTranslatorFromDescriptor(emptyString)
+	pdf.AddPage()
+
+	// Race title
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 10, tr(race.Title()), emptyString, 1, "L", false, 0, emptyString)
+	if race.ResultsVersion() > 0 {
+		pdf.SetFont("Helvetica", "B", 11)
+		if race.Amended() {
+			pdf.SetTextColor(192, 0, 0)
+		}
+		pdf.CellFormat(0, 7, race.VersionLabel(), emptyString, 1, "L", false, 0, emptyString)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.Ln(2)
+
+	// Header row
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.SetFillColor(217, 217, 217)
+	for col, header := range resultSheetHeaders {
+		pdf.CellFormat(resultSheetWidths[col], 9, header, "1", 0, "C", true, 0, emptyString)
+	}
+	pdf.Ln(-1)
+
+	// Result rows, school left aligned and everything else centred
+	pdf.SetFont("Courier", emptyString, 12)
+	for _, row := range RaceResultRows(race) {
+		for col, cell := range row {
+			align := "C"
+			if col == 4 { // School column
+				align = "L"
+			}
+			pdf.CellFormat(resultSheetWidths[col], 9, tr(cell), "1", 0, align, false, 0, emptyString)
+		}
+		pdf.Ln(-1)
+	}
+
+	// Who approved the results, for the record
+	if approval := race.LastApproval(); approval != nil && race.Approved {
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.MultiCell(0, 6, tr(approval.Describe()), emptyString, "L", false)
+	}
+}
+
+func outputPDF(w io.Writer, pdf *fpdf.Fpdf) error {
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write PDF: %v", err)
+	}
+	return nil
+}
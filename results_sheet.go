@@ -0,0 +1,68 @@
+package regattaClock
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// resultSheetHeaders are the column headings of a race's results sheet
+var resultSheetHeaders = []string{"OOF", "Place", "Split", "Time", "School"}
+
+// isStatusPlace reports whether place is a DQ/DNS/DNF code rather than a finish
+func isStatusPlace(place string) bool {
+	return place == "DQ" || place == "DNS" || place == "DNF"
+}
+
+// RaceResultRows returns the rows of a race's results sheet: numbered places
+// in finishing order, with dead heats such as "2=" together, followed by
+// DQ/DNS/DNF lanes
+func RaceResultRows(race RaceData) [][]string {
+	lanes := make([]int, 0, len(race.Lanes))
+	for lane := range race.Lanes {
+		lanes = append(lanes, lane)
+	}
+	sort.Ints(lanes)
+
+	// First add numerical places in order
+	placed := make([]int, 0)
+	for _, lane := range lanes {
+		if _, ok := timing.PlaceNumber(race.Lanes[lane].Place); ok {
+			placed = append(placed, lane)
+		}
+	}
+	sort.SliceStable(placed, func(i, j int) bool {
+		pi, _ := timing.PlaceNumber(race.Lanes[placed[i]].Place)
+		pj, _ := timing.PlaceNumber(race.Lanes[placed[j]].Place)
+		return pi < pj
+	})
+
+	rows := make([][]string, 0)
+	for _, lane := range placed {
+		entry := race.Lanes[lane]
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", lane),
+			entry.Place,
+			entry.Split,
+			entry.Time,
+			entry.SchoolName,
+		})
+	}
+
+	// Then add DQ/DNS/DNF entries
+	for _, lane := range lanes {
+		entry := race.Lanes[lane]
+		if isStatusPlace(entry.Place) {
+			rows = append(rows, []string{
+				fmt.Sprintf("Lane %d", lane),
+				entry.Place,
+				entry.Split,
+				entry.Time,
+				entry.SchoolName,
+			})
+		}
+	}
+
+	return rows
+}
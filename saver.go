@@ -0,0 +1,71 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+)
+
+// saveRace copies the race window's results into the regatta data and writes
+// them back into the source workbook
+func (a *App) saveRace(race RaceData) {
+	if a.regattaData == nil {
+		return
+	}
+
+	for i := range a.regattaData.Races {
+		if a.regattaData.Races[i].Key() != race.Key() {
+			continue
+		}
+		saved := &a.regattaData.Races[i]
+		a.storeResults(saved)
+
+		if err := WriteRaceResults(a.regattaData, *saved); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+
+		saved.Saved = true
+		dialog.ShowInformation("Save", fmt.Sprintf("%s results saved", saved.Key().Label()), a.window)
+		return
+	}
+
+	dialog.ShowError(fmt.Errorf("%s not found in regatta data", race.Key()), a.window)
+}
+
+// storeResults copies Place, Split and Time from the results table, and the
+// split station times from the session, into each scheduled lane of race
+func (a *App) storeResults(race *RaceData) {
+	for lane := 1; lane <= a.laneCount; lane++ {
+		entry, exists := race.Lanes[lane]
+		if !exists {
+			continue
+		}
+		entry.Place = a.resultsTable[3][lane]
+		entry.Split = a.resultsTable[4][lane]
+		entry.Time = a.resultsTable[5][lane]
+		entry.Splits = laneSplitTimes(a.snapshot.Lane(lane), a.snapshot.Rules.Precision)
+		race.Lanes[lane] = entry
+	}
+}
+
+// resultsRace returns a copy of race with the results table's Place, Split and
+// Time, and the split station times, in every lane that is scheduled or has a
+// result
+func (a *App) resultsRace(race RaceData) RaceData {
+	lanes := make(map[int]RaceEntry)
+	for lane := 1; lane <= a.laneCount; lane++ {
+		entry, exists := race.Lanes[lane]
+		if !exists && a.resultsTable[3][lane] == emptyString {
+			continue
+		}
+		entry.SchoolName = a.resultsTable[1][lane]
+		entry.Place = a.resultsTable[3][lane]
+		entry.Split = a.resultsTable[4][lane]
+		entry.Time = a.resultsTable[5][lane]
+		entry.Splits = laneSplitTimes(a.snapshot.Lane(lane), a.snapshot.Rules.Precision)
+		lanes[lane] = entry
+	}
+	race.Lanes = lanes
+	return race
+}
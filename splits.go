@@ -0,0 +1,302 @@
+package regattaClock
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// splitKeys capture a crossing at the first split stations, nearest the start first
+var splitKeys = []fyne.KeyName{fyne.KeyF5, fyne.KeyF6, fyne.KeyF7, fyne.KeyF8}
+
+// splitPanelHeight is the height of the split station capture lists
+const splitPanelHeight = 160
+
+// splitRow holds the widgets of a split station capture list row
+type splitRow struct {
+	station      int
+	index        int // Capture shown in the row, -1 before it is bound
+	laneEntry    *widget.Entry
+	timeLabel    *widget.Label
+	deleteButton *widget.Button
+}
+
+// showSplitStations lets the user set the distances of the regatta's split
+// stations
+func (a *App) showSplitStations() {
+	if a.regattaData == nil {
+		dialog.ShowError(fmt.Errorf("load a regatta before setting its split stations"), a.window)
+		return
+	}
+
+	distancesEntry := widget.NewEntry()
+	distancesEntry.SetText(timing.FormatSplitDistances(a.regattaData.Splits))
+	distancesEntry.SetPlaceHolder("500, 1000, 1500")
+
+	dialog.ShowForm(
+		"Split Stations",
+		"Apply",
+		"Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Distances (m)", distancesEntry),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			distances, err := timing.ParseSplitDistances(distancesEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			a.regattaData.Splits = distances
+			dialog.ShowInformation("Split Stations", "Race windows opened from now on will use the new split stations", a.window)
+		},
+		a.window,
+	)
+}
+
+// splitPanel creates a capture button and capture list for each of the
+// session's split stations
+func (a *App) splitPanel() fyne.CanvasObject {
+	distances := a.session.SplitStations()
+	if len(distances) == 0 {
+		return container.NewVBox()
+	}
+
+	a.splitRows = make(map[fyne.CanvasObject]*splitRow)
+	a.splitLists = make([]*widget.List, len(distances))
+	stations := container.NewGridWithColumns(len(distances))
+	for station, distance := range distances {
+		station := station
+		label := fmt.Sprintf("%dm Split", distance)
+		if station < len(splitKeys) {
+			label = fmt.Sprintf("%s (%s)", label, splitKeys[station])
+		}
+		captureButton := widget.NewButton(label, a.splitFunc(station))
+
+		list := widget.NewList(
+			func() int {
+				if station >= len(a.snapshot.Splits) {
+					return 0
+				}
+				return len(a.snapshot.Splits[station].Captures)
+			},
+			func() fyne.CanvasObject {
+				return a.newSplitRow(station)
+			},
+			a.bindSplitRow,
+		)
+		a.splitLists[station] = list
+
+		stations.Add(container.NewBorder(captureButton, nil, nil, nil, list))
+	}
+	return container.NewGridWrap(fyne.NewSize(lapTableWidth, splitPanelHeight), stations)
+}
+
+// newSplitRow creates the widgets for a split station capture list row
+func (a *App) newSplitRow(station int) fyne.CanvasObject {
+	row := &splitRow{station: station, index: -1}
+	row.laneEntry = widget.NewEntry()
+	row.laneEntry.SetPlaceHolder("Lane")
+	row.timeLabel = widget.NewLabel(emptyString)
+
+	// Split lanes can be entered while the race is still running
+	row.laneEntry.OnChanged = func(text string) {
+		a.assignSplit(row, text)
+	}
+	row.deleteButton = widget.NewButtonWithIcon(emptyString, theme.DeleteIcon(), func() {
+		if err := a.session.DeleteSplit(row.station, row.index); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		a.refreshContent()
+	})
+
+	item := container.NewBorder(nil, nil, nil, row.deleteButton,
+		container.NewGridWithColumns(2, row.laneEntry, row.timeLabel))
+	a.splitRows[item] = row
+	return item
+}
+
+// bindSplitRow shows a split capture from the current snapshot in a row
+func (a *App) bindSplitRow(id widget.ListItemID, item fyne.CanvasObject) {
+	row := a.splitRows[item]
+	if row == nil || row.station >= len(a.snapshot.Splits) || id >= len(a.snapshot.Splits[row.station].Captures) {
+		return
+	}
+	// Leave the lane alone while it is being typed in
+	editing := row.index == id && a.window.Canvas().Focused() == row.laneEntry
+	row.index = id
+
+	capture := a.snapshot.Splits[row.station].Captures[id]
+	if !editing {
+		lane := emptyString
+		if capture.Lane != 0 {
+			lane = strconv.Itoa(capture.Lane)
+		}
+		a.refreshing = true
+		row.laneEntry.SetText(lane)
+		a.refreshing = false
+	}
+	row.timeLabel.SetText(a.snapshot.Rules.Precision.Format(capture.Time))
+}
+
+// assignSplit assigns a split capture to the lane typed in its entry,
+// clearing the entry if that lane already has a split at the station
+func (a *App) assignSplit(row *splitRow, text string) {
+	if a.refreshing || row.index < 0 {
+		return
+	}
+
+	// Anything that is not a lane on this course unassigns the capture
+	laneNum, err := strconv.Atoi(text)
+	if err != nil || !a.validLane(laneNum) {
+		laneNum = 0
+	}
+	if err := a.session.AssignSplit(row.station, row.index, laneNum); err != nil {
+		// Duplicate lane, clear the input
+		a.session.AssignSplit(row.station, row.index, 0)
+		a.refreshing = true
+		row.laneEntry.SetText(emptyString)
+		a.refreshing = false
+	}
+	a.refreshContent()
+}
+
+// splitFunc captures a crossing at a split station
+func (a *App) splitFunc(station int) func() {
+	return func() {
+		if _, err := a.session.CaptureSplit(station); err != nil {
+			return
+		}
+		a.refreshContent()
+		if station < len(a.splitLists) {
+			a.splitLists[station].ScrollToBottom()
+		}
+	}
+}
+
+// refreshSplits redraws the split station capture lists
+func (a *App) refreshSplits() {
+	for _, list := range a.splitLists {
+		list.Refresh()
+	}
+}
+
+// laneSplitTimes returns a lane's time at each split station it was timed
+// at, or nil if it has none
+func laneSplitTimes(result timing.LaneResult, precision timing.Precision) []SplitTime {
+	var times []SplitTime
+	for station, split := range result.Splits {
+		if !split.Timed {
+			continue
+		}
+		splitTime := SplitTime{Distance: split.Distance, Time: precision.Format(split.Time)}
+		if station > 0 && split.HasSegment {
+			splitTime.Segment = precision.Format(split.Segment)
+		}
+		times = append(times, splitTime)
+	}
+	return times
+}
+
+// Text shows the split's time and, in brackets, its segment, such as
+// "03:31.0 [01:45.8]"
+func (s SplitTime) Text() string {
+	if s.Segment == emptyString {
+		return s.Time
+	}
+	return fmt.Sprintf("%s [%s]", s.Time, s.Segment)
+}
+
+// splitSummary is the Split shown for a lane: its time at each split station,
+// separated by slashes, or empty if it was not timed at any
+func splitSummary(splits []SplitTime) string {
+	texts := make([]string, len(splits))
+	for i, split := range splits {
+		texts[i] = split.Text()
+	}
+	return strings.Join(texts, " / ")
+}
+
+// splitAt returns a lane's split at a station, if it was timed there
+func splitAt(splits []SplitTime, distance int) (SplitTime, bool) {
+	for _, split := range splits {
+		if split.Distance == distance {
+			return split, true
+		}
+	}
+	return SplitTime{}, false
+}
+
+// splitsTable creates the referee approval table of each lane's split and
+// segment times, or nil if the race has no split stations
+func splitsTable(results timing.Results, race RaceData) fyne.CanvasObject {
+	if len(results.Splits) == 0 {
+		return nil
+	}
+	precision := results.Rules.Precision
+
+	headers := []string{"Lane"}
+	for _, station := range results.Splits {
+		headers = append(headers, fmt.Sprintf("%dm", station.Distance))
+	}
+	headers = append(headers, "Finish")
+
+	lanes := make([]int, 0)
+	for _, lane := range results.Lanes {
+		if _, scheduled := race.Lanes[lane.Lane]; scheduled || lane.Timed {
+			lanes = append(lanes, lane.Lane)
+		}
+	}
+	sort.Ints(lanes)
+
+	table := container.NewGridWithColumns(len(headers))
+	addCell := func(value string, bold bool) {
+		text := canvas.NewText(value, color.Black)
+		text.TextStyle = fyne.TextStyle{Bold: bold, Monospace: !bold}
+		text.Alignment = fyne.TextAlignCenter
+		text.TextSize = 24
+		table.Add(container.NewStack(canvas.NewRectangle(color.White), container.NewPadded(text)))
+	}
+
+	for _, header := range headers {
+		addCell(header, true)
+	}
+	// Each split shows its time from the start and, in brackets, the time
+	// since the lane's previous split
+	for _, lane := range lanes {
+		result := results.Lane(lane)
+		addCell(strconv.Itoa(lane), false)
+		for station, split := range result.Splits {
+			cell := emptyString
+			if split.Timed {
+				cell = precision.Format(split.Time)
+				if station > 0 && split.HasSegment {
+					cell += fmt.Sprintf(" [%s]", precision.Format(split.Segment))
+				}
+			}
+			addCell(cell, false)
+		}
+		cell := emptyString
+		if result.Timed {
+			cell = precision.Format(result.Time)
+			if segment, ok := result.FinishSegment(); ok {
+				cell += fmt.Sprintf(" [%s]", precision.Format(segment))
+			}
+		}
+		addCell(cell, false)
+	}
+	return table
+}
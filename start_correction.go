@@ -0,0 +1,108 @@
+package regattaClock
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+const (
+	noCorrection     = "No correction"
+	offsetCorrection = "Enter offset"
+	signalCorrection = "Use start signal"
+)
+
+func (a *App) startSignalFunc() func() {
+	return func() {
+		// Like a finish, a start signal is only captured while the race runs
+		a.session.CaptureStartSignal()
+	}
+}
+
+// showStartCorrection lets the user move the start of a stopped race by an
+// entered offset or to a captured start signal, showing each capture's
+// original and corrected time
+func (a *App) showStartCorrection() {
+	results := a.session.Results()
+	if results.State == timing.StateRunning {
+		dialog.ShowError(fmt.Errorf("stop the race before correcting the start"), a.window)
+		return
+	}
+	precision := results.Rules.Precision
+
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetPlaceHolder("+" + a.zeroTime())
+
+	signals := make([]string, len(results.StartSignals))
+	for i, signal := range results.StartSignals {
+		signals[i] = fmt.Sprintf("Signal %d at %s", i+1, signal.Format(precision))
+	}
+	signalSelect := widget.NewSelect(signals, nil)
+	if len(signals) == 0 {
+		signalSelect.PlaceHolder = "No start signals captured (F3)"
+		signalSelect.Disable()
+	}
+
+	choice := widget.NewRadioGroup([]string{noCorrection, offsetCorrection, signalCorrection}, nil)
+	choice.Required = true
+	choice.SetSelected(noCorrection)
+	if results.Correction != nil {
+		if results.Correction.Signal >= 0 {
+			choice.SetSelected(signalCorrection)
+			signalSelect.SetSelectedIndex(results.Correction.Signal)
+		} else {
+			choice.SetSelected(offsetCorrection)
+			offsetEntry.SetText(timing.FormatOffset(results.Correction.Offset, precision))
+		}
+	}
+
+	// Show what the correction did to each capture for the record
+	var captures strings.Builder
+	for i, capture := range results.Captures {
+		fmt.Fprintf(&captures, "Capture %d: %s captured, %s corrected\n", i+1,
+			capture.Original.Format(precision), capture.Elapsed.Format(precision))
+	}
+	current := "The start has not been corrected"
+	if results.Correction != nil {
+		current = fmt.Sprintf("Start corrected by %s from %s",
+			timing.FormatOffset(results.Correction.Offset, precision), results.Correction.Source())
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel(current),
+		choice,
+		widget.NewForm(
+			widget.NewFormItem("Offset", offsetEntry),
+			widget.NewFormItem("Start signal", signalSelect),
+		),
+		widget.NewLabel(captures.String()),
+	)
+
+	dialog.ShowCustomConfirm("Correct Start", "Apply", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var err error
+		switch choice.Selected {
+		case noCorrection:
+			a.session.ClearStartCorrection()
+		case offsetCorrection:
+			var offset timing.Duration
+			if offset, err = timing.ParseOffset(offsetEntry.Text); err == nil {
+				err = a.session.SetStartOffset(offset)
+			}
+		case signalCorrection:
+			err = a.session.UseStartSignal(signalSelect.SelectedIndex())
+		}
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		a.refreshContent()
+	}, a.window)
+}
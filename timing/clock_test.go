@@ -0,0 +1,148 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+var raceStart = time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+
+func TestManualClock(t *testing.T) {
+	clock := NewManualClock(raceStart)
+	if got := clock.Now(); !got.Equal(raceStart) {
+		t.Fatalf("Now() = %v, want %v", got, raceStart)
+	}
+	clock.Advance(90 * time.Second)
+	if got := clock.Now().Sub(raceStart); got != 90*time.Second {
+		t.Errorf("after Advance, Now() is %v after the start, want 1m30s", got)
+	}
+	clock.Set(raceStart.Add(time.Hour))
+	if got := clock.Now().Sub(raceStart); got != time.Hour {
+		t.Errorf("after Set, Now() is %v after the start, want 1h", got)
+	}
+}
+
+func TestReplayClock(t *testing.T) {
+	times := []time.Time{raceStart, raceStart.Add(time.Second), raceStart.Add(2 * time.Second)}
+	clock := NewReplayClock(times)
+	for i, want := range times {
+		if got := clock.Now(); !got.Equal(want) {
+			t.Errorf("step %d: Now() = %v, want %v", i, got, want)
+		}
+		if advanced := clock.Advance(); advanced != (i < len(times)-1) {
+			t.Errorf("step %d: Advance() = %v", i, advanced)
+		}
+	}
+	if got := clock.Now(); !got.Equal(times[len(times)-1]) {
+		t.Errorf("after the recording, Now() = %v, want the last timestamp", got)
+	}
+	if got := NewReplayClock(nil).Now(); !got.IsZero() {
+		t.Errorf("empty recording: Now() = %v, want the zero time", got)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		offsets []time.Duration // From the start; the first is the start itself
+		want    []Duration      // Elapsed time of each capture
+		wantErr bool
+	}{
+		{
+			name:    "start and finishes",
+			offsets: []time.Duration{0, 400 * time.Second, 401*time.Second + 250*time.Millisecond},
+			want:    []Duration{Duration(400 * time.Second), Duration(401*time.Second + 250*time.Millisecond)},
+		},
+		{
+			name:    "start only",
+			offsets: []time.Duration{0},
+			want:    []Duration{},
+		},
+		{
+			name:    "nothing recorded",
+			wantErr: true,
+		},
+		{
+			name:    "timestamps out of order",
+			offsets: []time.Duration{0, 400 * time.Second, 399 * time.Second},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times := make([]time.Time, len(tt.offsets))
+			for i, offset := range tt.offsets {
+				times[i] = raceStart.Add(offset)
+			}
+			session, err := Replay(3, times)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Replay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			results := session.Results()
+			if results.State != StateStopped {
+				t.Errorf("State = %v, want Stopped", results.State)
+			}
+			if len(results.Captures) != len(tt.want) {
+				t.Fatalf("%d captures, want %d", len(results.Captures), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := results.Captures[i].Elapsed; got != want {
+					t.Errorf("capture %d elapsed = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScriptedRaceWithStartSignal(t *testing.T) {
+	clock := NewManualClock(raceStart)
+	session := NewRaceSessionWithClock(3, clock)
+
+	if _, err := session.Capture(); err == nil {
+		t.Error("Capture() before the start: error = nil, want an error")
+	}
+	if err := session.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// The clock was started two seconds before the real start
+	clock.Advance(2 * time.Second)
+	if _, err := session.CaptureStartSignal(); err != nil {
+		t.Fatalf("CaptureStartSignal() error = %v", err)
+	}
+	clock.Advance(400 * time.Second)
+	if got := session.Elapsed(); got != Duration(402*time.Second) {
+		t.Errorf("Elapsed() = %v, want 06:42.0", got)
+	}
+	session.Capture()
+	clock.Advance(3 * time.Second)
+	session.Capture()
+	if err := session.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	session.AssignLane(0, 2)
+	session.AssignLane(1, 1)
+
+	if err := session.UseStartSignal(0); err != nil {
+		t.Fatalf("UseStartSignal() error = %v", err)
+	}
+	results := session.Results()
+	if got, want := results.Lane(2).Time, Duration(400*time.Second); got != want {
+		t.Errorf("lane 2 time = %v, want %v", got, want)
+	}
+	if got, want := results.Lane(1).Time, Duration(403*time.Second); got != want {
+		t.Errorf("lane 1 time = %v, want %v", got, want)
+	}
+	if results.Correction == nil || results.Correction.Signal != 0 {
+		t.Errorf("Correction = %+v, want start signal 1", results.Correction)
+	}
+
+	session.ClearStartCorrection()
+	if got, want := session.Results().Lane(2).Time, Duration(402*time.Second); got != want {
+		t.Errorf("after ClearStartCorrection, lane 2 time = %v, want %v", got, want)
+	}
+}
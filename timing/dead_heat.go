@@ -0,0 +1,95 @@
+package timing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// deadHeatMark follows a place shared by crews in a dead heat, as in "2="
+const deadHeatMark = "="
+
+// FormatPlace formats a finishing place, marking places shared in a dead heat
+func FormatPlace(place int, deadHeat bool) string {
+	if deadHeat {
+		return strconv.Itoa(place) + deadHeatMark
+	}
+	return strconv.Itoa(place)
+}
+
+// PlaceNumber returns the number of a finishing place such as "3" or "2=",
+// and false for place codes and empty places
+func PlaceNumber(place string) (int, bool) {
+	number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(place), deadHeatMark))
+	if err != nil || number < 1 {
+		return 0, false
+	}
+	return number, true
+}
+
+// SetDeadHeat ties a capture with the placed capture before it, so both
+// share a place and the place after them is skipped (1, 2=, 2=, 4). A dead
+// heat can only be declared between captures with the same published time.
+// Passing false separates them again.
+func (s *RaceSession) SetDeadHeat(index int, deadHeat bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkEditable(index); err != nil {
+		return err
+	}
+
+	if deadHeat && !s.captures[index].deadHeat {
+		previous := s.previousPlaced(index)
+		if previous < 0 {
+			return fmt.Errorf("capture %d has no placed capture before it to dead heat with", index+1)
+		}
+		if s.publishedTime(previous) != s.publishedTime(index) {
+			return fmt.Errorf("captures %d and %d do not have the same time", previous+1, index+1)
+		}
+	}
+	s.captures[index].deadHeat = deadHeat
+	s.record(Event{Kind: EventDeadHeat, Index: index, DeadHeat: deadHeat})
+	return nil
+}
+
+// placed reports whether a capture takes a finishing place. Unassigned
+// captures, such as false triggers, and captures whose lane has a place
+// code do not.
+func (s *RaceSession) placed(index int) bool {
+	lane := s.captures[index].lane
+	return lane != 0 && s.placeCodes[lane] == PlaceNone
+}
+
+// previousPlaced returns the index of the placed capture before index, or -1
+func (s *RaceSession) previousPlaced(index int) int {
+	for i := index - 1; i >= 0; i-- {
+		if s.placed(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// publishedTime returns a capture's calibrated time, rounded by the rules
+func (s *RaceSession) publishedTime(index int) Duration {
+	return s.rules.Round(s.captures[index].elapsed + s.startOffset() + s.calibration())
+}
+
+// calibration returns the shift that gives the winner the winning time. The
+// winner is the earliest placed capture, so a false trigger before it or a
+// capture moved ahead of it does not shift the published times.
+func (s *RaceSession) calibration() Duration {
+	if !s.calibrated {
+		return 0
+	}
+	winner := -1
+	for i := range s.captures {
+		if s.placed(i) && (winner < 0 || s.captures[i].elapsed < s.captures[winner].elapsed) {
+			winner = i
+		}
+	}
+	if winner < 0 {
+		return 0
+	}
+	return s.winningTime - (s.captures[winner].elapsed + s.startOffset())
+}
@@ -0,0 +1,119 @@
+package timing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a race time, such as the time from the start to a finish
+// crossing. It has nanosecond resolution and is only rounded when formatted
+// or published.
+type Duration time.Duration
+
+// ParseDuration parses a race time written as "ss.f", "mm:ss.ff" or
+// "h:mm:ss.fff". The digits after the decimal point are always a fraction of
+// a second, however many there are, so "1:02:03.4" is four tenths.
+func ParseDuration(value string) (Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("no time given")
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q: use ss.f, mm:ss.f or h:mm:ss.f", value)
+	}
+
+	// Seconds and the fraction are always last
+	secondsText, fractionText, hasFraction := strings.Cut(parts[len(parts)-1], ".")
+	seconds, err := parseTimeField(secondsText)
+	if err != nil {
+		return 0, fmt.Errorf("invalid seconds in %q", value)
+	}
+	if len(parts) > 1 && seconds >= 60 {
+		return 0, fmt.Errorf("invalid seconds in %q: must be less than 60", value)
+	}
+	total := time.Duration(seconds) * time.Second
+
+	if hasFraction {
+		fraction, err := parseFraction(fractionText)
+		if err != nil {
+			return 0, fmt.Errorf("invalid fraction of a second in %q", value)
+		}
+		total += fraction
+	}
+
+	if len(parts) >= 2 {
+		minutes, err := parseTimeField(parts[len(parts)-2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid minutes in %q", value)
+		}
+		if len(parts) == 3 && minutes >= 60 {
+			return 0, fmt.Errorf("invalid minutes in %q: must be less than 60", value)
+		}
+		total += time.Duration(minutes) * time.Minute
+	}
+
+	if len(parts) == 3 {
+		hours, err := parseTimeField(parts[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid hours in %q", value)
+		}
+		total += time.Duration(hours) * time.Hour
+	}
+
+	return Duration(total), nil
+}
+
+// parseTimeField parses an hours, minutes or whole seconds field
+func parseTimeField(digits string) (int, error) {
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, fmt.Errorf("not a number")
+	}
+	return strconv.Atoi(digits)
+}
+
+// parseFraction parses the digits after a decimal point as a fraction of a
+// second, so "4" is 400ms and "45" is 450ms
+func parseFraction(digits string) (time.Duration, error) {
+	if digits == "" || len(digits) > 9 || strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid fraction of a second")
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, err
+	}
+	for i := len(digits); i < 9; i++ {
+		value *= 10
+	}
+	return time.Duration(value), nil
+}
+
+// Format formats the duration as "mm:ss.f", or "h:mm:ss.f" from an hour
+// up, with one decimal place per digit of precision. Anything finer than the
+// precision is truncated; use Rules.Format to round for publishing.
+func (d Duration) Format(p Precision) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	total := time.Duration(d)
+	hours := total / time.Hour
+	minutes := total % time.Hour / time.Minute
+	seconds := total % time.Minute / time.Second
+	fraction := total % time.Second / time.Duration(p.Unit())
+
+	if hours > 0 {
+		return fmt.Sprintf("%s%d:%02d:%02d.%0*d", sign, hours, minutes, seconds, p.digits(), fraction)
+	}
+	return fmt.Sprintf("%s%02d:%02d.%0*d", sign, minutes, seconds, p.digits(), fraction)
+}
+
+// String formats the duration in tenths
+func (d Duration) String() string {
+	return d.Format(Tenths)
+}
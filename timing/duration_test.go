@@ -0,0 +1,124 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "45.3", want: 45*time.Second + 300*time.Millisecond},
+		{value: "7:01.4", want: 7*time.Minute + time.Second + 400*time.Millisecond},
+		{value: "07:01.45", want: 7*time.Minute + time.Second + 450*time.Millisecond},
+		{value: "1:02:03.4", want: time.Hour + 2*time.Minute + 3*time.Second + 400*time.Millisecond},
+		{value: " 6:59 ", want: 6*time.Minute + 59*time.Second},
+		{value: "0.001", want: time.Millisecond},
+		{value: "", wantErr: true},
+		{value: "7:60.0", wantErr: true},
+		{value: "1:60:00", wantErr: true},
+		{value: "1:2:3:4", wantErr: true},
+		{value: "7:0a.1", wantErr: true},
+		{value: "7:01.", wantErr: true},
+		{value: "-7:01.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != Duration(tt.want) {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.value, time.Duration(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	tests := []struct {
+		d         time.Duration
+		precision Precision
+		want      string
+	}{
+		{d: 7*time.Minute + time.Second + 450*time.Millisecond, precision: Tenths, want: "07:01.4"},
+		{d: 7*time.Minute + time.Second + 459*time.Millisecond, precision: Hundredths, want: "07:01.45"},
+		{d: 7*time.Minute + time.Second + 459*time.Millisecond, precision: Thousandths, want: "07:01.459"},
+		{d: time.Hour + 2*time.Minute + 3*time.Second, precision: Tenths, want: "1:02:03.0"},
+		{d: 0, precision: Hundredths, want: "00:00.00"},
+		{d: -1500 * time.Millisecond, precision: Tenths, want: "-00:01.5"},
+		{d: 5 * time.Second, precision: 0, want: "00:05.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Duration(tt.d).Format(tt.precision); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	for _, value := range []string{"00:45.3", "07:01.4", "1:02:03.4"} {
+		d, err := ParseDuration(value)
+		if err != nil {
+			t.Fatalf("ParseDuration(%q) error = %v", value, err)
+		}
+		if got := d.Format(Tenths); got != value {
+			t.Errorf("Format(ParseDuration(%q)) = %q", value, got)
+		}
+	}
+}
+
+func TestRulesRound(t *testing.T) {
+	raw := Duration(7*time.Minute + time.Second + 441*time.Millisecond)
+	tests := []struct {
+		name  string
+		rules Rules
+		d     Duration
+		want  string
+	}{
+		{name: "up to tenths", rules: Rules{Precision: Tenths, Rounding: RoundUp}, d: raw, want: "07:01.5"},
+		{name: "down to tenths", rules: Rules{Precision: Tenths, Rounding: RoundDown}, d: raw, want: "07:01.4"},
+		{name: "nearest tenth", rules: Rules{Precision: Tenths, Rounding: RoundNearest}, d: raw, want: "07:01.4"},
+		{name: "up to hundredths", rules: Rules{Precision: Hundredths, Rounding: RoundUp}, d: raw, want: "07:01.45"},
+		{name: "nearest hundredth", rules: Rules{Precision: Hundredths, Rounding: RoundNearest}, d: raw, want: "07:01.44"},
+		{name: "thousandths unchanged", rules: Rules{Precision: Thousandths, Rounding: RoundUp}, d: raw, want: "07:01.441"},
+		{name: "exact time not rounded up", rules: DefaultRules(), d: Duration(7*time.Minute + 400*time.Millisecond), want: "07:00.4"},
+		{name: "half rounds away from zero", rules: Rules{Precision: Tenths, Rounding: RoundNearest}, d: Duration(50 * time.Millisecond), want: "00:00.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Format(tt.d); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrecisionAndRounding(t *testing.T) {
+	for _, p := range Precisions {
+		got, err := ParsePrecision(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParsePrecision(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	for _, r := range RoundingPolicies {
+		got, err := ParseRounding(r.String())
+		if err != nil || got != r {
+			t.Errorf("ParseRounding(%q) = %v, %v", r.String(), got, err)
+		}
+	}
+	if _, err := ParsePrecision("minutes"); err == nil {
+		t.Error("ParsePrecision(\"minutes\") error = nil, want an error")
+	}
+	if _, err := ParseRounding("sideways"); err == nil {
+		t.Error("ParseRounding(\"sideways\") error = nil, want an error")
+	}
+}
@@ -0,0 +1,84 @@
+package timing
+
+import (
+	"fmt"
+)
+
+// DeleteCapture removes a capture, such as a stray lap press, with its lane
+// assignment. A lane it was assigned to is left without a finish or place,
+// and the placed captures after it each move up a place.
+func (s *RaceSession) DeleteCapture(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkEditable(index); err != nil {
+		return err
+	}
+	s.captures = append(s.captures[:index], s.captures[index+1:]...)
+	s.clearDeadHeat(index)
+	s.record(Event{Kind: EventDeleteCapture, Index: index})
+	return nil
+}
+
+// InsertCapture adds a finish the timer missed, at a time typed from the
+// corrected start. It goes into the capture list after the last capture
+// with an earlier or equal time, and its index is returned. Captures that
+// were moved keep their places in the finish order.
+func (s *RaceSession) InsertCapture(elapsed Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateStopped {
+		return 0, fmt.Errorf("captures can only be inserted once the race is stopped")
+	}
+	if elapsed < 0 {
+		return 0, fmt.Errorf("capture time cannot be negative")
+	}
+
+	inserted := capture{elapsed: elapsed - s.startOffset(), manual: true}
+	// The captures may be out of time order after a move, so they are scanned
+	// rather than searched
+	index := 0
+	for i, c := range s.captures {
+		if c.elapsed <= inserted.elapsed {
+			index = i + 1
+		}
+	}
+	s.captures = append(s.captures, capture{})
+	copy(s.captures[index+1:], s.captures[index:])
+	s.captures[index] = inserted
+	s.clearDeadHeat(index + 1)
+	s.record(Event{Kind: EventInsertCapture, Time: elapsed})
+	return index, nil
+}
+
+// MoveCapture moves a capture up (delta -1) or down (delta 1) the finish
+// order, keeping its time and lane
+func (s *RaceSession) MoveCapture(index int, delta int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkEditable(index); err != nil {
+		return err
+	}
+	if delta != -1 && delta != 1 {
+		return fmt.Errorf("captures move one place at a time")
+	}
+	other := index + delta
+	if other < 0 || other >= len(s.captures) {
+		return fmt.Errorf("capture %d cannot move further", index+1)
+	}
+
+	s.captures[index], s.captures[other] = s.captures[other], s.captures[index]
+	first := min(index, other)
+	s.clearDeadHeat(first)
+	s.clearDeadHeat(first + 1)
+	s.clearDeadHeat(first + 2)
+	s.record(Event{Kind: EventMoveCapture, Index: index, Delta: delta})
+	return nil
+}
+
+// clearDeadHeat separates a capture from the one before it after the finish
+// order around it changed. Out of range indexes are ignored.
+func (s *RaceSession) clearDeadHeat(index int) {
+	if index >= 0 && index < len(s.captures) {
+		s.captures[index].deadHeat = false
+	}
+}
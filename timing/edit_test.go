@@ -0,0 +1,180 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+// captureLanes returns the lane of each capture, in capture order
+func captureLanes(results Results) []int {
+	lanes := make([]int, len(results.Captures))
+	for i, c := range results.Captures {
+		lanes[i] = c.Lane
+	}
+	return lanes
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDeleteCapture(t *testing.T) {
+	finishes := []finish{{395 * time.Second, 0}, {400 * time.Second, 1}, {401 * time.Second, 2}, {402 * time.Second, 3}}
+	tests := []struct {
+		name      string
+		index     int
+		wantLanes []int    // Lane of each remaining capture
+		want      []string // Place of each lane, lane 1 first
+		wantErr   bool
+	}{
+		{
+			name:      "false trigger",
+			index:     0,
+			wantLanes: []int{1, 2, 3},
+			want:      []string{"1", "2", "3"},
+		},
+		{
+			name:      "placed capture takes its lane's place with it",
+			index:     1,
+			wantLanes: []int{0, 2, 3},
+			want:      []string{"", "1", "2"},
+		},
+		{
+			name:    "no such capture",
+			index:   4,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 3, finishes)
+			err := session.DeleteCapture(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteCapture(%d) error = %v, wantErr %v", tt.index, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			results := session.Results()
+			if got := captureLanes(results); !equalInts(got, tt.wantLanes) {
+				t.Errorf("capture lanes = %v, want %v", got, tt.wantLanes)
+			}
+			if got := lanePlaces(results); !equalStrings(got, tt.want) {
+				t.Errorf("lane places = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertCapture(t *testing.T) {
+	tests := []struct {
+		name      string
+		moves     []int // Captures moved up one place before inserting
+		elapsed   time.Duration
+		wantIndex int
+		wantErr   bool
+	}{
+		{name: "before every capture", elapsed: 399 * time.Second, wantIndex: 0},
+		{name: "between captures", elapsed: 401*time.Second + 500*time.Millisecond, wantIndex: 2},
+		{name: "tie goes after", elapsed: 401 * time.Second, wantIndex: 2},
+		{name: "after every capture", elapsed: 410 * time.Second, wantIndex: 3},
+		// The 402s capture was moved ahead of the 401s one, so the captures
+		// are out of time order
+		{name: "after a move", moves: []int{2}, elapsed: 401*time.Second + 500*time.Millisecond, wantIndex: 3},
+		{name: "negative time", elapsed: -time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 4, []finish{{400 * time.Second, 1}, {401 * time.Second, 2}, {402 * time.Second, 3}})
+			for _, index := range tt.moves {
+				must(t, session.MoveCapture(index, -1))
+			}
+			index, err := session.InsertCapture(Duration(tt.elapsed))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InsertCapture(%v) error = %v, wantErr %v", tt.elapsed, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if index != tt.wantIndex {
+				t.Errorf("InsertCapture(%v) = %d, want %d", tt.elapsed, index, tt.wantIndex)
+			}
+			inserted := session.Results().Captures[index]
+			if !inserted.Manual || inserted.Lane != 0 || inserted.Elapsed != Duration(tt.elapsed) {
+				t.Errorf("inserted capture = %+v, want an unassigned manual capture at %v", inserted, tt.elapsed)
+			}
+		})
+	}
+}
+
+func TestInsertCaptureWhileRunning(t *testing.T) {
+	session := NewRaceSessionWithClock(2, NewManualClock(raceStart))
+	must(t, session.Start())
+	if _, err := session.InsertCapture(Duration(time.Minute)); err == nil {
+		t.Error("InsertCapture() while running: error = nil, want an error")
+	}
+	if err := session.DeleteCapture(0); err == nil {
+		t.Error("DeleteCapture() while running: error = nil, want an error")
+	}
+}
+
+func TestMoveCapture(t *testing.T) {
+	tests := []struct {
+		name      string
+		index     int
+		delta     int
+		wantLanes []int
+		want      []string
+		wantErr   bool
+	}{
+		{name: "up", index: 1, delta: -1, wantLanes: []int{2, 1, 3}, want: []string{"2", "1", "3"}},
+		{name: "down", index: 1, delta: 1, wantLanes: []int{1, 3, 2}, want: []string{"1", "3", "2"}},
+		{name: "past the top", index: 0, delta: -1, wantErr: true},
+		{name: "past the bottom", index: 2, delta: 1, wantErr: true},
+		{name: "more than one place", index: 0, delta: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 3, []finish{{400 * time.Second, 1}, {401 * time.Second, 2}, {402 * time.Second, 3}})
+			err := session.MoveCapture(tt.index, tt.delta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveCapture(%d, %d) error = %v, wantErr %v", tt.index, tt.delta, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			results := session.Results()
+			if got := captureLanes(results); !equalInts(got, tt.wantLanes) {
+				t.Errorf("capture lanes = %v, want %v", got, tt.wantLanes)
+			}
+			if got := lanePlaces(results); !equalStrings(got, tt.want) {
+				t.Errorf("lane places = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveCaptureClearsDeadHeat(t *testing.T) {
+	session := stoppedRace(t, 3, []finish{{400 * time.Second, 1}, {400 * time.Second, 2}, {402 * time.Second, 3}})
+	must(t, session.SetDeadHeat(1, true))
+	must(t, session.MoveCapture(2, -1))
+	for i, c := range session.Results().Captures {
+		if c.DeadHeat {
+			t.Errorf("capture %d is still a dead heat after the move", i+1)
+		}
+	}
+	if got, want := lanePlaces(session.Results()), []string{"1", "3", "2"}; !equalStrings(got, want) {
+		t.Errorf("lane places = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,448 @@
+package timing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Crew is a boat in a head race, identified by its bow number
+type Crew struct {
+	Bow   int
+	Event string // Crews are ranked against others in the same event
+	Name  string
+}
+
+// headCapture is a crossing of the start or finish line
+type headCapture struct {
+	at  Duration // Time since the session started
+	bow int      // Zero until assigned
+}
+
+// HeadRaceSession times a head race, where crews start one at a time and
+// each crew's time is its own finish less its own start. Start and finish
+// crossings are captured in separate lists and matched to crews by bow
+// number. It is safe for concurrent use.
+type HeadRaceSession struct {
+	mu         sync.Mutex
+	clock      Clock
+	rules      Rules
+	state      State
+	startTime  time.Time
+	crews      map[int]Crew
+	bows       []int // Bow numbers in the order crews were added
+	starts     []headCapture
+	finishes   []headCapture
+	placeCodes map[int]PlaceCode
+	recorder   Recorder // Receives every change, nil if not recorded
+}
+
+// NewHeadRaceSession creates a cleared head race session that reads its
+// times from clock, or the system clock if clock is nil
+func NewHeadRaceSession(clock Clock) *HeadRaceSession {
+	if clock == nil {
+		clock = RealClock{}
+	}
+	return &HeadRaceSession{
+		clock:      clock,
+		rules:      DefaultRules(),
+		crews:      make(map[int]Crew),
+		placeCodes: make(map[int]PlaceCode),
+	}
+}
+
+// SetRecorder sends every later change to the session to recorder, or stops
+// recording when it is nil
+func (s *HeadRaceSession) SetRecorder(recorder Recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder = recorder
+}
+
+// record sends an event to the recorder, if there is one. The session lock
+// must be held.
+func (s *HeadRaceSession) record(event Event) {
+	if event.At.IsZero() {
+		event.At = s.clock.Now()
+	}
+	if s.recorder != nil {
+		s.recorder.Record(event)
+	}
+}
+
+// Now returns the current time on the session's clock
+func (s *HeadRaceSession) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clock.Now()
+}
+
+// AddCrew adds a crew to the session. Crews are expected to start in the
+// order they are added.
+func (s *HeadRaceSession) AddCrew(crew Crew) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if crew.Bow < 1 {
+		return fmt.Errorf("bow number must be 1 or more, got %d", crew.Bow)
+	}
+	if _, exists := s.crews[crew.Bow]; exists {
+		return fmt.Errorf("bow %d is already in the session", crew.Bow)
+	}
+	s.crews[crew.Bow] = crew
+	s.bows = append(s.bows, crew.Bow)
+	s.record(Event{Kind: EventAddCrew, Crew: &crew})
+	return nil
+}
+
+// Crews returns the crews in the order they were added
+func (s *HeadRaceSession) Crews() []Crew {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	crews := make([]Crew, len(s.bows))
+	for i, bow := range s.bows {
+		crews[i] = s.crews[bow]
+	}
+	return crews
+}
+
+// Rules returns the precision and rounding used for results
+func (s *HeadRaceSession) Rules() Rules {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rules
+}
+
+// SetRules sets the precision and rounding used for results
+func (s *HeadRaceSession) SetRules(rules Rules) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+	s.record(Event{Kind: EventRules, Rules: &rules})
+}
+
+// State returns the current session state
+func (s *HeadRaceSession) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Elapsed returns the time since the session started, or zero if it is not running
+func (s *HeadRaceSession) Elapsed() Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0
+	}
+	return Duration(s.clock.Now().Sub(s.startTime))
+}
+
+// Start starts the session clock that start and finish crossings are timed
+// against. The session must be cleared first.
+func (s *HeadRaceSession) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateCleared {
+		return fmt.Errorf("head race must be cleared before it can start")
+	}
+	s.startTime = s.clock.Now()
+	s.state = StateRunning
+	s.record(Event{Kind: EventStart, At: s.startTime})
+	return nil
+}
+
+// CaptureStart records a crew crossing the start line and returns its index.
+// It is assigned to the next crew in start order that has not started, and
+// can be reassigned like a finish if crews start out of order.
+func (s *HeadRaceSession) CaptureStart() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0, fmt.Errorf("head race is not running")
+	}
+
+	started := make(map[int]bool)
+	for _, c := range s.starts {
+		started[c.bow] = true
+	}
+	next := 0
+	for _, bow := range s.bows {
+		if !started[bow] {
+			next = bow
+			break
+		}
+	}
+
+	at := Duration(s.clock.Now().Sub(s.startTime))
+	s.starts = append(s.starts, headCapture{at: at, bow: next})
+	s.record(Event{Kind: EventCrewStart, Time: at})
+	return len(s.starts) - 1, nil
+}
+
+// CaptureFinish records a crew crossing the finish line and returns its
+// index. It stays unassigned until its bow number is entered.
+func (s *HeadRaceSession) CaptureFinish() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0, fmt.Errorf("head race is not running")
+	}
+	at := Duration(s.clock.Now().Sub(s.startTime))
+	s.finishes = append(s.finishes, headCapture{at: at})
+	s.record(Event{Kind: EventCrewFinish, Time: at})
+	return len(s.finishes) - 1, nil
+}
+
+// Stop stops the session clock
+func (s *HeadRaceSession) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return fmt.Errorf("head race is not running")
+	}
+	s.state = StateStopped
+	s.record(Event{Kind: EventStop})
+	return nil
+}
+
+// Clear discards all captures and place codes, keeping the crews
+func (s *HeadRaceSession) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("head race must be stopped before it can be cleared")
+	}
+	s.state = StateCleared
+	s.startTime = time.Time{}
+	s.starts = nil
+	s.finishes = nil
+	s.placeCodes = make(map[int]PlaceCode)
+	s.record(Event{Kind: EventClear})
+	return nil
+}
+
+// AssignStart assigns a start capture to a bow number, or unassigns it when
+// bow is zero. A capture already assigned the bow takes this capture's old
+// bow in exchange, so two crews that started out of order can be swapped.
+// Bow numbers can be corrected while the session is running.
+func (s *HeadRaceSession) AssignStart(index int, bow int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.assign(s.starts, "start", index, bow); err != nil {
+		return err
+	}
+	s.record(Event{Kind: EventAssignStart, Index: index, Bow: bow})
+	return nil
+}
+
+// AssignFinish assigns a finish capture to a bow number, or unassigns it
+// when bow is zero. Like AssignStart, it swaps bows with a capture already
+// assigned the bow.
+func (s *HeadRaceSession) AssignFinish(index int, bow int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.assign(s.finishes, "finish", index, bow); err != nil {
+		return err
+	}
+	s.record(Event{Kind: EventAssignFinish, Index: index, Bow: bow})
+	return nil
+}
+
+func (s *HeadRaceSession) assign(captures []headCapture, line string, index int, bow int) error {
+	if index < 0 || index >= len(captures) {
+		return fmt.Errorf("no %s capture %d", line, index+1)
+	}
+	if bow != 0 {
+		if _, exists := s.crews[bow]; !exists {
+			return fmt.Errorf("no crew with bow %d", bow)
+		}
+		for i, c := range captures {
+			if i != index && c.bow == bow {
+				captures[i].bow = captures[index].bow
+			}
+		}
+	}
+	captures[index].bow = bow
+	return nil
+}
+
+// SetBow renumbers a crew, such as when the bow numbers in the draw are
+// wrong. Its captures and place code follow it to the new number.
+func (s *HeadRaceSession) SetBow(bow int, newBow int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	crew, exists := s.crews[bow]
+	if !exists {
+		return fmt.Errorf("no crew with bow %d", bow)
+	}
+	if newBow == bow {
+		return nil
+	}
+	if newBow < 1 {
+		return fmt.Errorf("bow number must be 1 or more, got %d", newBow)
+	}
+	if _, taken := s.crews[newBow]; taken {
+		return fmt.Errorf("bow %d is already in the session", newBow)
+	}
+
+	delete(s.crews, bow)
+	crew.Bow = newBow
+	s.crews[newBow] = crew
+	for i := range s.bows {
+		if s.bows[i] == bow {
+			s.bows[i] = newBow
+		}
+	}
+	for _, captures := range [][]headCapture{s.starts, s.finishes} {
+		for i := range captures {
+			if captures[i].bow == bow {
+				captures[i].bow = newBow
+			}
+		}
+	}
+	if code, coded := s.placeCodes[bow]; coded {
+		delete(s.placeCodes, bow)
+		s.placeCodes[newBow] = code
+	}
+	s.record(Event{Kind: EventRenumberCrew, Bow: bow, NewBow: newBow})
+	return nil
+}
+
+// SetPlaceCode marks a crew DNS, DNF or DQ, or returns it to ranking by
+// elapsed time with PlaceNone
+func (s *HeadRaceSession) SetPlaceCode(bow int, code PlaceCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.crews[bow]; !exists {
+		return fmt.Errorf("no crew with bow %d", bow)
+	}
+	if _, err := ParsePlaceCode(string(code)); err != nil {
+		return err
+	}
+	if code == PlaceNone {
+		delete(s.placeCodes, bow)
+	} else {
+		s.placeCodes[bow] = code
+	}
+	s.record(Event{Kind: EventPlaceCode, Bow: bow, Code: code})
+	return nil
+}
+
+// HeadCapture is a start or finish crossing as shown in a capture list
+type HeadCapture struct {
+	Bow  int      // Zero if unassigned
+	Time Duration // Time since the session started
+}
+
+// HeadCrewResult is a crew's result in a head race
+type HeadCrewResult struct {
+	Crew
+	Place    string // Place within the event, place code, or empty if not finished
+	Timed    bool   // Whether the crew is ranked with an elapsed time
+	Started  bool
+	Finished bool
+	Start    Duration // Start crossing, since the session started
+	Finish   Duration // Finish crossing, since the session started
+	Elapsed  Duration // Finish less start, rounded by the session rules
+}
+
+// HeadResults is a snapshot of a head race session
+type HeadResults struct {
+	State    State
+	Rules    Rules
+	Starts   []HeadCapture
+	Finishes []HeadCapture
+	Events   []string         // Events in the order their first crew was added
+	Crews    []HeadCrewResult // Grouped by event, each ranked by elapsed time
+}
+
+// Results returns a snapshot of the session. Within each event, crews with
+// both a start and a finish are ranked by elapsed time, followed by crews
+// with a place code and then crews still on the course. Crews with the same
+// time at the session's precision are placed in a dead heat.
+func (s *HeadRaceSession) Results() HeadResults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := HeadResults{
+		State:    s.state,
+		Rules:    s.rules,
+		Starts:   make([]HeadCapture, len(s.starts)),
+		Finishes: make([]HeadCapture, len(s.finishes)),
+	}
+
+	crewResults := make(map[int]*HeadCrewResult)
+	eventIndex := make(map[string]int)
+	for _, bow := range s.bows {
+		crew := s.crews[bow]
+		crewResults[bow] = &HeadCrewResult{Crew: crew}
+		if _, seen := eventIndex[crew.Event]; !seen {
+			eventIndex[crew.Event] = len(results.Events)
+			results.Events = append(results.Events, crew.Event)
+		}
+	}
+
+	for i, c := range s.starts {
+		results.Starts[i] = HeadCapture{Bow: c.bow, Time: c.at}
+		if result, exists := crewResults[c.bow]; exists {
+			result.Started = true
+			result.Start = c.at
+		}
+	}
+	for i, c := range s.finishes {
+		results.Finishes[i] = HeadCapture{Bow: c.bow, Time: c.at}
+		if result, exists := crewResults[c.bow]; exists {
+			result.Finished = true
+			result.Finish = c.at
+		}
+	}
+
+	// Rank finished crews within each event by their raw elapsed time
+	for _, event := range results.Events {
+		ranked := make([]*HeadCrewResult, 0)
+		coded := make([]*HeadCrewResult, 0)
+		onCourse := make([]*HeadCrewResult, 0)
+		for _, bow := range s.bows {
+			result := crewResults[bow]
+			if result.Event != event {
+				continue
+			}
+			switch {
+			case s.placeCodes[bow] != PlaceNone:
+				result.Place = string(s.placeCodes[bow])
+				coded = append(coded, result)
+			case result.Started && result.Finished:
+				ranked = append(ranked, result)
+			default:
+				onCourse = append(onCourse, result)
+			}
+		}
+
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].Finish-ranked[i].Start < ranked[j].Finish-ranked[j].Start
+		})
+		// Crews with the same published time share a place (1, 2=, 2=, 4)
+		places := make([]int, len(ranked))
+		shared := make(map[int]int)
+		for i, result := range ranked {
+			result.Timed = true
+			result.Elapsed = s.rules.Round(result.Finish - result.Start)
+			places[i] = i + 1
+			if i > 0 && result.Elapsed == ranked[i-1].Elapsed {
+				places[i] = places[i-1]
+			}
+			shared[places[i]]++
+		}
+		for i, result := range ranked {
+			result.Place = FormatPlace(places[i], shared[places[i]] > 1)
+		}
+
+		for _, group := range [][]*HeadCrewResult{ranked, coded, onCourse} {
+			for _, result := range group {
+				results.Crews = append(results.Crews, *result)
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,270 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+// crossing is a start or finish crossing in a scripted head race: its time
+// since the session started and the bow it is assigned to, zero to leave it
+// unassigned
+type crossing struct {
+	finish bool
+	at     time.Duration
+	bow    int
+}
+
+// headPlace is a crew's line in head race results
+type headPlace struct {
+	bow     int
+	place   string
+	elapsed Duration // Zero if the crew is not timed
+}
+
+// headCrews are two crews in the first event and one in the second, in
+// start order
+var headCrews = []Crew{
+	{Bow: 1, Event: "Men's 8+", Name: "Alpha"},
+	{Bow: 2, Event: "Men's 8+", Name: "Bravo"},
+	{Bow: 3, Event: "Women's 8+", Name: "Charlie"},
+}
+
+// stoppedHeadRace times a head race on a manual clock, capturing each
+// crossing in time order, then stops it and assigns the bows
+func stoppedHeadRace(t *testing.T, crossings []crossing) *HeadRaceSession {
+	t.Helper()
+	clock := NewManualClock(raceStart)
+	session := NewHeadRaceSession(clock)
+	for _, crew := range headCrews {
+		must(t, session.AddCrew(crew))
+	}
+	must(t, session.Start())
+
+	starts := make([]int, 0)
+	finishes := make([]int, 0)
+	for _, c := range crossings {
+		clock.Set(raceStart.Add(c.at))
+		if c.finish {
+			_, err := session.CaptureFinish()
+			must(t, err)
+			finishes = append(finishes, c.bow)
+		} else {
+			_, err := session.CaptureStart()
+			must(t, err)
+			starts = append(starts, c.bow)
+		}
+	}
+	must(t, session.Stop())
+	for i, bow := range starts {
+		must(t, session.AssignStart(i, bow))
+	}
+	for i, bow := range finishes {
+		must(t, session.AssignFinish(i, bow))
+	}
+	return session
+}
+
+func TestHeadRaceResults(t *testing.T) {
+	tests := []struct {
+		name      string
+		crossings []crossing
+		codes     map[int]PlaceCode
+		want      []headPlace // Results in order, each event ranked in turn
+	}{
+		{
+			name: "ranked within each event",
+			crossings: []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 2},
+				{false, 60 * time.Second, 3},
+				{true, 310 * time.Second, 1},
+				{true, 330 * time.Second, 2},
+				{true, 400 * time.Second, 3},
+			},
+			// Bravo finished after Alpha but started 30s later
+			want: []headPlace{
+				{2, "1", Duration(300 * time.Second)},
+				{1, "2", Duration(310 * time.Second)},
+				{3, "1", Duration(340 * time.Second)},
+			},
+		},
+		{
+			name: "same elapsed time is a dead heat",
+			crossings: []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 2},
+				{true, 300 * time.Second, 1},
+				{true, 330 * time.Second, 2},
+			},
+			want: []headPlace{
+				{1, "1=", Duration(300 * time.Second)},
+				{2, "1=", Duration(300 * time.Second)},
+				{3, "", 0},
+			},
+		},
+		{
+			name: "missing start",
+			crossings: []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 0},
+				{true, 310 * time.Second, 1},
+				{true, 330 * time.Second, 2},
+			},
+			want: []headPlace{
+				{1, "1", Duration(310 * time.Second)},
+				{2, "", 0},
+				{3, "", 0},
+			},
+		},
+		{
+			name: "missing finish",
+			crossings: []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 2},
+				{true, 330 * time.Second, 2},
+			},
+			want: []headPlace{
+				{2, "1", Duration(300 * time.Second)},
+				{1, "", 0},
+				{3, "", 0},
+			},
+		},
+		{
+			name: "place code ranks after timed crews",
+			crossings: []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 2},
+				{true, 310 * time.Second, 1},
+				{true, 330 * time.Second, 2},
+			},
+			codes: map[int]PlaceCode{2: PlaceDQ},
+			want: []headPlace{
+				{1, "1", Duration(310 * time.Second)},
+				{2, "DQ", 0},
+				{3, "", 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedHeadRace(t, tt.crossings)
+			for bow, code := range tt.codes {
+				must(t, session.SetPlaceCode(bow, code))
+			}
+			crews := session.Results().Crews
+			if len(crews) != len(tt.want) {
+				t.Fatalf("%d crews in results, want %d", len(crews), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := headPlace{bow: crews[i].Bow, place: crews[i].Place}
+				if crews[i].Timed {
+					got.elapsed = crews[i].Elapsed
+				}
+				if got != want {
+					t.Errorf("result %d = %+v, want %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadRaceAssignStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   int
+		bow     int
+		want    []int // Bow of each start capture
+		wantErr bool
+	}{
+		{name: "out of order start swaps bows", index: 0, bow: 2, want: []int{2, 1}},
+		{name: "bow not yet started", index: 1, bow: 3, want: []int{1, 3}},
+		{name: "unassign", index: 0, bow: 0, want: []int{0, 2}},
+		{name: "no such crew", index: 0, bow: 9, wantErr: true},
+		{name: "no such capture", index: 2, bow: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(raceStart)
+			session := NewHeadRaceSession(clock)
+			for _, crew := range headCrews {
+				must(t, session.AddCrew(crew))
+			}
+			must(t, session.Start())
+			// Starts go to the next crew in start order until corrected
+			for i := 0; i < 2; i++ {
+				clock.Advance(30 * time.Second)
+				_, err := session.CaptureStart()
+				must(t, err)
+			}
+
+			err := session.AssignStart(tt.index, tt.bow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssignStart(%d, %d) error = %v, wantErr %v", tt.index, tt.bow, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			starts := session.Results().Starts
+			got := make([]int, len(starts))
+			for i, c := range starts {
+				got[i] = c.Bow
+			}
+			if !equalInts(got, tt.want) {
+				t.Errorf("start bows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadRaceSetBow(t *testing.T) {
+	tests := []struct {
+		name    string
+		bow     int
+		newBow  int
+		wantErr bool
+	}{
+		{name: "renumber", bow: 1, newBow: 11},
+		{name: "same number", bow: 1, newBow: 1},
+		{name: "number taken", bow: 1, newBow: 2, wantErr: true},
+		{name: "zero", bow: 1, newBow: 0, wantErr: true},
+		{name: "no such crew", bow: 9, newBow: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedHeadRace(t, []crossing{
+				{false, 0, 1},
+				{false, 30 * time.Second, 2},
+				{true, 310 * time.Second, 1},
+				{true, 330 * time.Second, 2},
+			})
+			must(t, session.SetPlaceCode(1, PlaceDQ))
+			err := session.SetBow(tt.bow, tt.newBow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetBow(%d, %d) error = %v, wantErr %v", tt.bow, tt.newBow, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// The crew keeps its captures, place code and start order
+			results := session.Results()
+			if got := results.Starts[0].Bow; got != tt.newBow {
+				t.Errorf("start 1 bow = %d, want %d", got, tt.newBow)
+			}
+			if got := results.Finishes[0].Bow; got != tt.newBow {
+				t.Errorf("finish 1 bow = %d, want %d", got, tt.newBow)
+			}
+			if got := session.Crews()[0]; got.Bow != tt.newBow || got.Name != "Alpha" {
+				t.Errorf("first crew = %+v, want Alpha as bow %d", got, tt.newBow)
+			}
+			for _, crew := range results.Crews {
+				if crew.Name == "Alpha" && (crew.Bow != tt.newBow || crew.Place != "DQ") {
+					t.Errorf("Alpha = bow %d place %q, want bow %d place DQ", crew.Bow, crew.Place, tt.newBow)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package timing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventKind identifies what happened in a journaled race session
+type EventKind string
+
+const (
+	EventOpen                 EventKind = "open" // First event, with the lane count and application data
+	EventRules                EventKind = "rules"
+	EventSplitStations        EventKind = "splitStations"
+	EventStart                EventKind = "start"
+	EventCapture              EventKind = "capture"
+	EventStartSignal          EventKind = "startSignal"
+	EventCaptureSplit         EventKind = "captureSplit"
+	EventStop                 EventKind = "stop"
+	EventClear                EventKind = "clear"
+	EventAssignLane           EventKind = "assignLane"
+	EventAssignSplit          EventKind = "assignSplit"
+	EventDeleteSplit          EventKind = "deleteSplit"
+	EventPlaceCode            EventKind = "placeCode"
+	EventCaptureTime          EventKind = "captureTime"
+	EventDeadHeat             EventKind = "deadHeat"
+	EventDeleteCapture        EventKind = "deleteCapture"
+	EventInsertCapture        EventKind = "insertCapture"
+	EventMoveCapture          EventKind = "moveCapture"
+	EventWinningTime          EventKind = "winningTime"
+	EventClearWinningTime     EventKind = "clearWinningTime"
+	EventStartOffset          EventKind = "startOffset"
+	EventUseStartSignal       EventKind = "useStartSignal"
+	EventClearStartCorrection EventKind = "clearStartCorrection"
+	EventUndo                 EventKind = "undo"
+	EventRedo                 EventKind = "redo"
+	EventAddCrew              EventKind = "addCrew"    // Head races only, as are the crew, assign and renumber events
+	EventCrewStart            EventKind = "crewStart"  // A crew crossing the start line
+	EventCrewFinish           EventKind = "crewFinish" // A crew crossing the finish line
+	EventAssignStart          EventKind = "assignStart"
+	EventAssignFinish         EventKind = "assignFinish"
+	EventRenumberCrew         EventKind = "renumberCrew"
+	EventApproved             EventKind = "approved"  // Recorded by the application, not the session
+	EventProtested            EventKind = "protested" // Approved results reopened for review, recorded by the application
+	EventClosed               EventKind = "closed"    // Last event of a session that was closed normally
+)
+
+// Event is a single change to a race session, as written to its journal.
+// Only the fields the kind needs are set.
+type Event struct {
+	Kind EventKind `json:"kind"`
+	At   time.Time `json:"at"` // Wall clock time of the event; the start time for EventStart
+	// Time is the monotonic time since the start for captures and start
+	// signals, or the time or offset entered for edits
+	Time      Duration        `json:"time,omitempty"`
+	Index     int             `json:"index,omitempty"`
+	Station   int             `json:"station,omitempty"`
+	Lane      int             `json:"lane,omitempty"`
+	Delta     int             `json:"delta,omitempty"`
+	Code      PlaceCode       `json:"code,omitempty"`
+	DeadHeat  bool            `json:"deadHeat,omitempty"`
+	Rules     *Rules          `json:"rules,omitempty"`
+	Distances []int           `json:"distances,omitempty"`
+	LaneCount int             `json:"laneCount,omitempty"`
+	Meta      json.RawMessage `json:"meta,omitempty"` // Application data describing the race
+	Head      bool            `json:"head,omitempty"` // Whether an open event starts a head race session
+	Bow       int             `json:"bow,omitempty"`
+	NewBow    int             `json:"newBow,omitempty"` // Bow a crew was renumbered to
+	Crew      *Crew           `json:"crew,omitempty"`
+}
+
+// Recorder receives every change made to a race session
+type Recorder interface {
+	Record(event Event)
+}
+
+// SetRecorder sends every later change to the session to recorder, or stops
+// recording when it is nil
+func (s *RaceSession) SetRecorder(recorder Recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder = recorder
+}
+
+// record adds an event to the session's undo history and sends it to the
+// recorder, if there is one. The session lock must be held.
+func (s *RaceSession) record(event Event) {
+	if event.At.IsZero() {
+		event.At = s.clock.Now()
+	}
+	if event.Kind != EventUndo && event.Kind != EventRedo {
+		s.keep(event)
+	}
+	if s.recorder != nil {
+		s.recorder.Record(event)
+	}
+}
+
+// EventLog keeps a race session's events in memory, passing each one on to
+// another recorder such as a Journal. It is safe for concurrent use.
+type EventLog struct {
+	mu     sync.Mutex
+	events []Event
+	next   Recorder
+}
+
+// NewEventLog creates an event log holding events that were already recorded,
+// such as those replayed from a journal. New events are passed on to next
+// unless it is nil.
+func NewEventLog(events []Event, next Recorder) *EventLog {
+	return &EventLog{events: append([]Event(nil), events...), next: next}
+}
+
+// Record keeps an event and passes it on
+func (l *EventLog) Record(event Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	if l.next != nil {
+		l.next.Record(event)
+	}
+}
+
+// Events returns the events recorded so far, oldest first
+func (l *EventLog) Events() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+// Journal is an append-only file of race session events, one JSON object per
+// line. Every event is flushed to disk before Record returns, so a crash
+// loses nothing that was recorded. It is safe for concurrent use.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+	err  error // First write error
+}
+
+// CreateJournal creates a new journal file, failing if it already exists
+func CreateJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal: %v", err)
+	}
+	return &Journal{file: file, path: path}, nil
+}
+
+// OpenJournal opens an existing journal to append to it, such as after it
+// has been replayed
+func OpenJournal(path string) (*Journal, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+	// Drop a last line torn by a crash so the next event starts on its own line
+	if complete := bytes.LastIndexByte(content, '\n') + 1; complete < len(content) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, fmt.Errorf("failed to repair journal: %v", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %v", err)
+	}
+	return &Journal{file: file, path: path}, nil
+}
+
+// Path returns the journal's file path
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Record appends an event and syncs it to disk. Write errors are kept and
+// returned by Err, so recording never interrupts timing.
+func (j *Journal) Record(event Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return
+	}
+	line, err := json.Marshal(event)
+	if err == nil {
+		_, err = j.file.Write(append(line, '\n'))
+	}
+	if err == nil {
+		err = j.file.Sync()
+	}
+	if err != nil && j.err == nil {
+		j.err = fmt.Errorf("failed to write journal: %v", err)
+	}
+}
+
+// Err returns the first error writing to the journal
+func (j *Journal) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close journal: %v", err)
+	}
+	return nil
+}
+
+// ReadJournal reads the events of a journal file. A last line torn by a
+// crash is ignored.
+func ReadJournal(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %v", err)
+	}
+	defer file.Close()
+	return readEvents(file)
+}
+
+func readEvents(r io.Reader) ([]Event, error) {
+	events := make([]Event, 0)
+	var torn error
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if torn != nil {
+			return nil, torn
+		}
+		var event Event
+		if err := json.Unmarshal(text, &event); err != nil {
+			// Only the last line may be incomplete
+			torn = fmt.Errorf("journal line %d is not a valid event: %v", line, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+	if len(events) == 0 || events[0].Kind != EventOpen {
+		return nil, fmt.Errorf("journal does not start with an open event")
+	}
+	return events, nil
+}
+
+// Finished reports whether a journal's session was closed normally
+func Finished(events []Event) bool {
+	return len(events) > 0 && events[len(events)-1].Kind == EventClosed
+}
+
+// ReplayJournal rebuilds a race session from its journal. A session that was
+// running when the journal ended carries on running against the system
+// clock from its recorded start time.
+func ReplayJournal(events []Event) (*RaceSession, error) {
+	if len(events) == 0 || events[0].Kind != EventOpen {
+		return nil, fmt.Errorf("journal does not start with an open event")
+	}
+	if events[0].Head {
+		return nil, fmt.Errorf("journal is of a head race")
+	}
+
+	clock := NewManualClock(events[0].At)
+	session := NewRaceSessionWithClock(events[0].LaneCount, clock)
+	for i, event := range events[1:] {
+		if err := session.apply(clock, event); err != nil {
+			return nil, fmt.Errorf("failed to replay journal event %d (%s): %v", i+2, event.Kind, err)
+		}
+	}
+
+	session.mu.Lock()
+	session.clock = RealClock{}
+	session.mu.Unlock()
+	return session, nil
+}
+
+// apply makes the change an event records, reading captured times from clock
+func (s *RaceSession) apply(clock *ManualClock, event Event) error {
+	// Captures are replayed at their monotonic time since the start
+	captureAt := func() {
+		s.mu.Lock()
+		start := s.startTime
+		s.mu.Unlock()
+		clock.Set(start.Add(time.Duration(event.Time)))
+	}
+
+	var err error
+	switch event.Kind {
+	case EventRules:
+		if event.Rules == nil {
+			return fmt.Errorf("no rules")
+		}
+		s.SetRules(*event.Rules)
+	case EventSplitStations:
+		err = s.SetSplitStations(event.Distances)
+	case EventStart:
+		clock.Set(event.At)
+		err = s.Start()
+	case EventCapture:
+		captureAt()
+		_, err = s.Capture()
+	case EventStartSignal:
+		captureAt()
+		_, err = s.CaptureStartSignal()
+	case EventCaptureSplit:
+		captureAt()
+		_, err = s.CaptureSplit(event.Station)
+	case EventStop:
+		err = s.Stop()
+	case EventClear:
+		err = s.Clear()
+	case EventAssignLane:
+		err = s.AssignLane(event.Index, event.Lane)
+	case EventAssignSplit:
+		err = s.AssignSplit(event.Station, event.Index, event.Lane)
+	case EventDeleteSplit:
+		err = s.DeleteSplit(event.Station, event.Index)
+	case EventPlaceCode:
+		err = s.SetPlaceCode(event.Lane, event.Code)
+	case EventCaptureTime:
+		err = s.SetCaptureTime(event.Index, event.Time)
+	case EventDeadHeat:
+		err = s.SetDeadHeat(event.Index, event.DeadHeat)
+	case EventDeleteCapture:
+		err = s.DeleteCapture(event.Index)
+	case EventInsertCapture:
+		_, err = s.InsertCapture(event.Time)
+	case EventMoveCapture:
+		err = s.MoveCapture(event.Index, event.Delta)
+	case EventWinningTime:
+		s.SetWinningTime(event.Time)
+	case EventClearWinningTime:
+		s.ClearWinningTime()
+	case EventStartOffset:
+		err = s.SetStartOffset(event.Time)
+	case EventUseStartSignal:
+		err = s.UseStartSignal(event.Index)
+	case EventClearStartCorrection:
+		s.ClearStartCorrection()
+	case EventUndo:
+		err = s.Undo()
+	case EventRedo:
+		err = s.Redo()
+	case EventOpen, EventApproved, EventProtested, EventClosed:
+		// Application events that do not change the session
+	default:
+		return fmt.Errorf("unknown event")
+	}
+	return err
+}
+
+// ReplayHeadJournal rebuilds a head race session, with its crews, from its
+// journal. Like ReplayJournal, a session that was running carries on against
+// the system clock.
+func ReplayHeadJournal(events []Event) (*HeadRaceSession, error) {
+	if len(events) == 0 || events[0].Kind != EventOpen {
+		return nil, fmt.Errorf("journal does not start with an open event")
+	}
+	if !events[0].Head {
+		return nil, fmt.Errorf("journal is not of a head race")
+	}
+
+	clock := NewManualClock(events[0].At)
+	session := NewHeadRaceSession(clock)
+	for i, event := range events[1:] {
+		if err := session.apply(clock, event); err != nil {
+			return nil, fmt.Errorf("failed to replay journal event %d (%s): %v", i+2, event.Kind, err)
+		}
+	}
+
+	session.mu.Lock()
+	session.clock = RealClock{}
+	session.mu.Unlock()
+	return session, nil
+}
+
+// apply makes the change an event records, reading captured times from clock
+func (s *HeadRaceSession) apply(clock *ManualClock, event Event) error {
+	// Crossings are replayed at their monotonic time since the start
+	captureAt := func() {
+		s.mu.Lock()
+		start := s.startTime
+		s.mu.Unlock()
+		clock.Set(start.Add(time.Duration(event.Time)))
+	}
+
+	var err error
+	switch event.Kind {
+	case EventAddCrew:
+		if event.Crew == nil {
+			return fmt.Errorf("no crew")
+		}
+		err = s.AddCrew(*event.Crew)
+	case EventRules:
+		if event.Rules == nil {
+			return fmt.Errorf("no rules")
+		}
+		s.SetRules(*event.Rules)
+	case EventStart:
+		clock.Set(event.At)
+		err = s.Start()
+	case EventCrewStart:
+		captureAt()
+		_, err = s.CaptureStart()
+	case EventCrewFinish:
+		captureAt()
+		_, err = s.CaptureFinish()
+	case EventStop:
+		err = s.Stop()
+	case EventClear:
+		err = s.Clear()
+	case EventAssignStart:
+		err = s.AssignStart(event.Index, event.Bow)
+	case EventAssignFinish:
+		err = s.AssignFinish(event.Index, event.Bow)
+	case EventRenumberCrew:
+		err = s.SetBow(event.Bow, event.NewBow)
+	case EventPlaceCode:
+		err = s.SetPlaceCode(event.Bow, event.Code)
+	case EventOpen, EventClosed:
+		// Application events that do not change the session
+	default:
+		return fmt.Errorf("unknown event")
+	}
+	return err
+}
@@ -0,0 +1,286 @@
+package timing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// journaledRace scripts a race through a session on a manual clock,
+// recording its events to a log that starts with an open event
+func journaledRace(t *testing.T, laneCount int, script func(*RaceSession, *ManualClock)) (*RaceSession, *EventLog) {
+	t.Helper()
+	clock := NewManualClock(raceStart)
+	session := NewRaceSessionWithClock(laneCount, clock)
+	log := NewEventLog(nil, nil)
+	log.Record(Event{Kind: EventOpen, At: raceStart, LaneCount: laneCount})
+	session.SetRecorder(log)
+	script(session, clock)
+	return session, log
+}
+
+// must fails the test on an error from a scripted step
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayJournalResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(*testing.T, *RaceSession, *ManualClock)
+	}{
+		{
+			name: "lanes, codes and winning time",
+			script: func(t *testing.T, s *RaceSession, clock *ManualClock) {
+				must(t, s.Start())
+				for _, at := range []time.Duration{400 * time.Second, 401 * time.Second, 405 * time.Second} {
+					clock.Set(raceStart.Add(at))
+					_, err := s.Capture()
+					must(t, err)
+				}
+				must(t, s.Stop())
+				must(t, s.AssignLane(0, 3))
+				must(t, s.AssignLane(1, 1))
+				must(t, s.AssignLane(2, 2))
+				must(t, s.SetPlaceCode(1, PlaceDQ))
+				s.SetWinningTime(Duration(399 * time.Second))
+			},
+		},
+		{
+			name: "start correction",
+			script: func(t *testing.T, s *RaceSession, clock *ManualClock) {
+				must(t, s.Start())
+				clock.Advance(1500 * time.Millisecond)
+				_, err := s.CaptureStartSignal()
+				must(t, err)
+				clock.Advance(400*time.Second + 123*time.Millisecond)
+				_, err = s.Capture()
+				must(t, err)
+				clock.Advance(2 * time.Second)
+				_, err = s.Capture()
+				must(t, err)
+				must(t, s.Stop())
+				must(t, s.AssignLane(0, 1))
+				must(t, s.AssignLane(1, 2))
+				must(t, s.SetStartOffset(Duration(-time.Second)))
+				must(t, s.UseStartSignal(0))
+			},
+		},
+		{
+			name: "undo and redo",
+			script: func(t *testing.T, s *RaceSession, clock *ManualClock) {
+				must(t, s.Start())
+				clock.Advance(400 * time.Second)
+				_, err := s.Capture()
+				must(t, err)
+				clock.Advance(time.Second)
+				_, err = s.Capture()
+				must(t, err)
+				must(t, s.Stop())
+				must(t, s.AssignLane(0, 2))
+				must(t, s.AssignLane(1, 1))
+				must(t, s.SetPlaceCode(1, PlaceDNF))
+				must(t, s.DeleteCapture(1))
+				must(t, s.Undo()) // Capture 2 is back
+				must(t, s.Undo()) // Lane 1 is placed again
+				must(t, s.Redo()) // Lane 1 is DNF again
+				must(t, s.SetStartOffset(Duration(500*time.Millisecond)))
+				must(t, s.Undo())
+			},
+		},
+		{
+			name: "edits and split stations",
+			script: func(t *testing.T, s *RaceSession, clock *ManualClock) {
+				must(t, s.SetSplitStations([]int{1000}))
+				must(t, s.Start())
+				clock.Advance(200 * time.Second)
+				_, err := s.CaptureSplit(0)
+				must(t, err)
+				clock.Advance(200 * time.Second)
+				_, err = s.Capture()
+				must(t, err)
+				_, err = s.Capture()
+				must(t, err)
+				must(t, s.Stop())
+				must(t, s.AssignSplit(0, 0, 1))
+				must(t, s.AssignLane(0, 1))
+				must(t, s.AssignLane(1, 2))
+				must(t, s.SetDeadHeat(1, true))
+				_, err = s.InsertCapture(Duration(410 * time.Second))
+				must(t, err)
+				must(t, s.MoveCapture(2, -1))
+				must(t, s.SetCaptureTime(1, Duration(409*time.Second)))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, log := journaledRace(t, 3, func(s *RaceSession, clock *ManualClock) {
+				tt.script(t, s, clock)
+			})
+			replayed, err := ReplayJournal(log.Events())
+			if err != nil {
+				t.Fatalf("ReplayJournal() error = %v", err)
+			}
+			if got, want := replayed.Results(), session.Results(); !reflect.DeepEqual(got, want) {
+				t.Errorf("replayed results differ\n got: %+v\nwant: %+v", got, want)
+			}
+			if got, want := replayed.History(), session.History(); !reflect.DeepEqual(got, want) {
+				t.Errorf("replayed history = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	session, _ := journaledRace(t, 2, func(s *RaceSession, clock *ManualClock) {
+		must(t, s.Start())
+		clock.Advance(400 * time.Second)
+		s.Capture()
+		must(t, s.Stop())
+	})
+
+	if err := session.Undo(); err == nil {
+		t.Error("Undo() with no edits: error = nil, want an error")
+	}
+	must(t, session.AssignLane(0, 1))
+	must(t, session.SetPlaceCode(1, PlaceDQ))
+	// Typing a winning time is one edit
+	session.SetWinningTime(Duration(4 * time.Minute))
+	session.ClearWinningTime()
+	session.SetWinningTime(Duration(401 * time.Second))
+	if got := len(session.History()); got != 3 {
+		t.Fatalf("%d history entries, want 3", got)
+	}
+
+	must(t, session.Undo())
+	if session.Results().Calibrated {
+		t.Error("after undoing the winning time, Calibrated = true")
+	}
+	must(t, session.Undo())
+	if got := session.Results().Lane(1).Place; got != "1" {
+		t.Errorf("after undoing DQ, lane 1 place = %q, want \"1\"", got)
+	}
+	if !session.CanRedo() {
+		t.Fatal("CanRedo() = false after undo")
+	}
+	must(t, session.Redo())
+	if got := session.Results().Lane(1).Place; got != "DQ" {
+		t.Errorf("after redo, lane 1 place = %q, want \"DQ\"", got)
+	}
+
+	// A new edit drops what was left to redo
+	must(t, session.AssignLane(0, 2))
+	if session.CanRedo() {
+		t.Error("CanRedo() = true after a new edit")
+	}
+}
+
+func TestJournalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "race-1.jsonl")
+	journal, err := CreateJournal(path)
+	if err != nil {
+		t.Fatalf("CreateJournal() error = %v", err)
+	}
+	session, log := journaledRace(t, 2, func(s *RaceSession, clock *ManualClock) {
+		must(t, s.Start())
+		clock.Advance(400*time.Second + 7*time.Millisecond)
+		s.Capture()
+		must(t, s.Stop())
+		must(t, s.AssignLane(0, 2))
+	})
+	for _, event := range log.Events() {
+		journal.Record(event)
+	}
+	must(t, journal.Err())
+	must(t, journal.Close())
+
+	// A write cut short by a crash leaves a torn last line
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	must(t, err)
+	file.WriteString(`{"kind":"assignLa`)
+	file.Close()
+
+	events, err := ReadJournal(path)
+	if err != nil {
+		t.Fatalf("ReadJournal() error = %v", err)
+	}
+	if Finished(events) {
+		t.Error("Finished() = true for a journal with no closed event")
+	}
+	replayed, err := ReplayJournal(events)
+	if err != nil {
+		t.Fatalf("ReplayJournal() error = %v", err)
+	}
+	if got, want := replayed.Results(), session.Results(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed results differ\n got: %+v\nwant: %+v", got, want)
+	}
+
+	// Reopening carries on after the last whole event
+	journal, err = OpenJournal(path)
+	must(t, err)
+	journal.Record(Event{Kind: EventClosed, At: raceStart})
+	must(t, journal.Close())
+	events, err = ReadJournal(path)
+	must(t, err)
+	if !Finished(events) {
+		t.Error("Finished() = false after a closed event")
+	}
+}
+
+func TestReplayJournalNeedsOpenEvent(t *testing.T) {
+	if _, err := ReplayJournal([]Event{{Kind: EventStart, At: raceStart}}); err == nil {
+		t.Error("ReplayJournal() without an open event: error = nil, want an error")
+	}
+}
+
+func TestReplayHeadJournal(t *testing.T) {
+	clock := NewManualClock(raceStart)
+	session := NewHeadRaceSession(clock)
+	log := NewEventLog(nil, nil)
+	log.Record(Event{Kind: EventOpen, At: raceStart, Head: true})
+	session.SetRecorder(log)
+
+	for _, crew := range headCrews {
+		must(t, session.AddCrew(crew))
+	}
+	must(t, session.Start())
+	for i := 0; i < 3; i++ {
+		clock.Advance(30 * time.Second)
+		_, err := session.CaptureStart()
+		must(t, err)
+	}
+	for i := 0; i < 3; i++ {
+		clock.Advance(100 * time.Second)
+		_, err := session.CaptureFinish()
+		must(t, err)
+	}
+	must(t, session.AssignStart(0, 2)) // Bravo went off first
+	must(t, session.AssignFinish(0, 1))
+	must(t, session.AssignFinish(1, 3))
+	must(t, session.AssignFinish(2, 2))
+	must(t, session.SetBow(3, 30))
+	must(t, session.SetPlaceCode(30, PlaceDQ))
+
+	replayed, err := ReplayHeadJournal(log.Events())
+	if err != nil {
+		t.Fatalf("ReplayHeadJournal() error = %v", err)
+	}
+	if got, want := replayed.Results(), session.Results(); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed results differ\n got: %+v\nwant: %+v", got, want)
+	}
+
+	// Each kind of session only replays its own journals
+	if _, err := ReplayJournal(log.Events()); err == nil {
+		t.Error("ReplayJournal() of a head race: error = nil, want an error")
+	}
+	if _, err := ReplayHeadJournal([]Event{{Kind: EventOpen, At: raceStart, LaneCount: 6}}); err == nil {
+		t.Error("ReplayHeadJournal() of a side by side race: error = nil, want an error")
+	}
+}
@@ -0,0 +1,133 @@
+package timing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Precision is the number of decimal places times are shown and published to
+type Precision int
+
+const (
+	Tenths      Precision = 1
+	Hundredths  Precision = 2
+	Thousandths Precision = 3
+)
+
+// Precisions lists the supported precisions, coarsest first
+var Precisions = []Precision{Tenths, Hundredths, Thousandths}
+
+func (p Precision) String() string {
+	switch p {
+	case Tenths:
+		return "Tenths"
+	case Hundredths:
+		return "Hundredths"
+	case Thousandths:
+		return "Thousandths"
+	}
+	return fmt.Sprintf("Precision(%d)", int(p))
+}
+
+// ParsePrecision returns the precision for a name such as "hundredths"
+func ParsePrecision(name string) (Precision, error) {
+	for _, p := range Precisions {
+		if strings.EqualFold(name, p.String()) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown timing precision %q", name)
+}
+
+// Unit returns the smallest time step at this precision
+func (p Precision) Unit() Duration {
+	switch p {
+	case Hundredths:
+		return Duration(10 * time.Millisecond)
+	case Thousandths:
+		return Duration(time.Millisecond)
+	}
+	return Duration(100 * time.Millisecond)
+}
+
+// digits returns the number of decimal places, treating an unset precision
+// as tenths
+func (p Precision) digits() int {
+	if p < Tenths || p > Thousandths {
+		return int(Tenths)
+	}
+	return int(p)
+}
+
+// Format formats a duration at this precision, truncating anything finer so
+// a running clock never shows a time before it has been reached
+func (p Precision) Format(d Duration) string {
+	return d.Format(p)
+}
+
+// Rounding is how times finer than the precision are rounded for results
+type Rounding int
+
+const (
+	RoundUp      Rounding = iota // To the next unit, as rowing rules require
+	RoundDown                    // Truncate
+	RoundNearest                 // Half away from zero
+)
+
+// RoundingPolicies lists the supported rounding policies
+var RoundingPolicies = []Rounding{RoundUp, RoundDown, RoundNearest}
+
+func (r Rounding) String() string {
+	switch r {
+	case RoundUp:
+		return "Round up"
+	case RoundDown:
+		return "Round down"
+	case RoundNearest:
+		return "Round to nearest"
+	}
+	return fmt.Sprintf("Rounding(%d)", int(r))
+}
+
+// ParseRounding returns the rounding policy for a name such as "round up"
+func ParseRounding(name string) (Rounding, error) {
+	for _, r := range RoundingPolicies {
+		if strings.EqualFold(name, r.String()) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rounding policy %q", name)
+}
+
+// Rules are a regatta's timing precision and rounding policy
+type Rules struct {
+	Precision Precision `json:"precision"`
+	Rounding  Rounding  `json:"rounding"`
+}
+
+// DefaultRules are tenths of a second, rounded up
+func DefaultRules() Rules {
+	return Rules{Precision: Tenths, Rounding: RoundUp}
+}
+
+// Round rounds a duration to the precision using the rounding policy
+func (r Rules) Round(d Duration) Duration {
+	unit := time.Duration(r.Precision.Unit())
+	switch r.Rounding {
+	case RoundDown:
+		return Duration(time.Duration(d).Truncate(unit))
+	case RoundNearest:
+		return Duration(time.Duration(d).Round(unit))
+	}
+	rounded := time.Duration(d).Truncate(unit)
+	if rounded < time.Duration(d) {
+		rounded += unit
+	}
+	return Duration(rounded)
+}
+
+// Format rounds a duration for publishing and formats it at the precision
+func (r Rules) Format(d Duration) string {
+	return r.Precision.Format(r.Round(d))
+}
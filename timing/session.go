@@ -0,0 +1,445 @@
+// Package timing is the race timing engine behind the regatta clock. It
+// captures finish times, assigns them to lanes and works out places and
+// calibrated times without depending on any user interface.
+package timing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State is where a race session is in its start, stop and clear cycle
+type State int
+
+const (
+	StateCleared State = iota // Ready to start
+	StateRunning              // Clock running, finishes can be captured
+	StateStopped              // Clock stopped, results can be edited
+)
+
+func (s State) String() string {
+	switch s {
+	case StateCleared:
+		return "Cleared"
+	case StateRunning:
+		return "Running"
+	case StateStopped:
+		return "Stopped"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// PlaceCode marks a lane that did not get a finishing place
+type PlaceCode string
+
+const (
+	PlaceNone PlaceCode = ""    // Placed by finish order
+	PlaceDNS  PlaceCode = "DNS" // Did not start
+	PlaceDNF  PlaceCode = "DNF" // Did not finish
+	PlaceDQ   PlaceCode = "DQ"  // Disqualified
+)
+
+// ParsePlaceCode returns the place code for DNS, DNF, DQ or an empty string
+func ParsePlaceCode(value string) (PlaceCode, error) {
+	switch code := PlaceCode(value); code {
+	case PlaceNone, PlaceDNS, PlaceDNF, PlaceDQ:
+		return code, nil
+	}
+	return PlaceNone, fmt.Errorf("unknown place code %q", value)
+}
+
+// capture is a single finish crossing
+type capture struct {
+	elapsed  Duration // Time since the clock was started, as captured
+	lane     int      // Zero until assigned
+	deadHeat bool     // Shares a place with the placed capture before it
+	manual   bool     // Inserted by hand after the race
+}
+
+// RaceSession times a single race: one start, a finish capture per crossing,
+// and the lane assignments, place codes and winning time entered afterwards.
+// It is safe for concurrent use.
+type RaceSession struct {
+	mu          sync.Mutex
+	clock       Clock
+	rules       Rules
+	laneCount   int
+	state       State
+	startTime   time.Time
+	captures    []capture
+	placeCodes  map[int]PlaceCode
+	winningTime Duration
+	calibrated  bool
+
+	startSignals []Duration       // Start signals captured since the clock was started
+	correction   *StartCorrection // Nil until the start is corrected
+
+	splitDistances []int            // Metres from the start of each split station
+	splits         [][]splitCapture // Captures at each split station
+
+	recorder Recorder // Nil unless the session is journaled
+	applied  []Event  // Every change in effect, oldest first, for undo
+	redo     []Event  // Undone edits, most recently undone last
+}
+
+// NewRaceSession creates a cleared session for a course with laneCount lanes,
+// timed by the system clock
+func NewRaceSession(laneCount int) *RaceSession {
+	return NewRaceSessionWithClock(laneCount, RealClock{})
+}
+
+// NewRaceSessionWithClock creates a cleared session that reads its times
+// from clock
+func NewRaceSessionWithClock(laneCount int, clock Clock) *RaceSession {
+	if clock == nil {
+		clock = RealClock{}
+	}
+	return &RaceSession{
+		clock:      clock,
+		rules:      DefaultRules(),
+		laneCount:  laneCount,
+		placeCodes: make(map[int]PlaceCode),
+	}
+}
+
+// LaneCount returns the number of lanes on the course
+func (s *RaceSession) LaneCount() int {
+	return s.laneCount
+}
+
+// Rules returns the precision and rounding used for results
+func (s *RaceSession) Rules() Rules {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rules
+}
+
+// SetRules sets the precision and rounding used for results. Captures are
+// kept at full resolution, so the rules can change at any time.
+func (s *RaceSession) SetRules(rules Rules) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+	s.record(Event{Kind: EventRules, Rules: &rules})
+}
+
+// State returns the current session state
+func (s *RaceSession) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Elapsed returns the time since the start, or zero if the race has not started
+func (s *RaceSession) Elapsed() Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0
+	}
+	return Duration(s.clock.Now().Sub(s.startTime))
+}
+
+// Now returns the time on the session's clock, for recording when things
+// happened to the race on the same timeline as its captures
+func (s *RaceSession) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clock.Now()
+}
+
+// Start starts the race clock. The session must be cleared first.
+func (s *RaceSession) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateCleared {
+		return fmt.Errorf("race must be cleared before it can start")
+	}
+	s.startTime = s.clock.Now()
+	s.state = StateRunning
+	s.record(Event{Kind: EventStart, At: s.startTime})
+	return nil
+}
+
+// Capture records a finish crossing at the current time and returns its index
+func (s *RaceSession) Capture() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0, fmt.Errorf("race is not running")
+	}
+	elapsed := Duration(s.clock.Now().Sub(s.startTime))
+	s.captures = append(s.captures, capture{elapsed: elapsed})
+	s.record(Event{Kind: EventCapture, Time: elapsed})
+	return len(s.captures) - 1, nil
+}
+
+// Stop stops the race clock so results can be edited
+func (s *RaceSession) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return fmt.Errorf("race is not running")
+	}
+	s.state = StateStopped
+	s.record(Event{Kind: EventStop})
+	return nil
+}
+
+// Clear discards all captures, lane assignments, place codes and the winning
+// time, ready for a new start
+func (s *RaceSession) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("race must be stopped before it can be cleared")
+	}
+	s.state = StateCleared
+	s.startTime = time.Time{}
+	s.captures = nil
+	s.placeCodes = make(map[int]PlaceCode)
+	s.winningTime = 0
+	s.calibrated = false
+	s.startSignals = nil
+	s.correction = nil
+	s.splits = make([][]splitCapture, len(s.splitDistances))
+	s.record(Event{Kind: EventClear})
+	return nil
+}
+
+// AssignLane assigns a capture to a lane, or unassigns it when lane is zero.
+// A lane can only be assigned to one capture.
+func (s *RaceSession) AssignLane(index int, lane int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkEditable(index); err != nil {
+		return err
+	}
+	if lane != 0 {
+		if err := s.checkLane(lane); err != nil {
+			return err
+		}
+		for i, c := range s.captures {
+			if i != index && c.lane == lane {
+				return fmt.Errorf("lane %d is already assigned to capture %d", lane, i+1)
+			}
+		}
+	}
+	s.captures[index].lane = lane
+	s.record(Event{Kind: EventAssignLane, Index: index, Lane: lane})
+	return nil
+}
+
+// SetPlaceCode marks a lane DNS, DNF or DQ, or returns it to finish order
+// placing with PlaceNone. Lanes after it in finish order move up a place.
+func (s *RaceSession) SetPlaceCode(lane int, code PlaceCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("results cannot be edited while the race is running")
+	}
+	if err := s.checkLane(lane); err != nil {
+		return err
+	}
+	if _, err := ParsePlaceCode(string(code)); err != nil {
+		return err
+	}
+	if code == PlaceNone {
+		delete(s.placeCodes, lane)
+	} else {
+		s.placeCodes[lane] = code
+	}
+	s.record(Event{Kind: EventPlaceCode, Lane: lane, Code: code})
+	return nil
+}
+
+// SetCaptureTime corrects the elapsed time of a capture. The time is given
+// from the corrected start, as Results shows it.
+func (s *RaceSession) SetCaptureTime(index int, elapsed Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkEditable(index); err != nil {
+		return err
+	}
+	if elapsed < 0 {
+		return fmt.Errorf("capture time cannot be negative")
+	}
+	s.captures[index].elapsed = elapsed - s.startOffset()
+	s.record(Event{Kind: EventCaptureTime, Index: index, Time: elapsed})
+	return nil
+}
+
+// SetWinningTime calibrates the results so the first capture is given the
+// official winning time, shifting every other capture by the same amount
+func (s *RaceSession) SetWinningTime(winningTime Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.winningTime = winningTime
+	s.calibrated = true
+	s.record(Event{Kind: EventWinningTime, Time: winningTime})
+}
+
+// ClearWinningTime removes the winning time calibration
+func (s *RaceSession) ClearWinningTime() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.winningTime = 0
+	s.calibrated = false
+	s.record(Event{Kind: EventClearWinningTime})
+}
+
+func (s *RaceSession) checkEditable(index int) error {
+	if s.state == StateRunning {
+		return fmt.Errorf("results cannot be edited while the race is running")
+	}
+	if index < 0 || index >= len(s.captures) {
+		return fmt.Errorf("no capture %d", index+1)
+	}
+	return nil
+}
+
+func (s *RaceSession) checkLane(lane int) error {
+	if lane < 1 || lane > s.laneCount {
+		return fmt.Errorf("lane %d is not between 1 and %d", lane, s.laneCount)
+	}
+	return nil
+}
+
+// CaptureResult is a capture as shown in the capture list
+type CaptureResult struct {
+	Lane     int    // Zero if unassigned
+	Place    string // Finishing place, such as "2=" in a dead heat, or the lane's place code
+	DeadHeat bool   // Shares its place with the placed capture before it
+	Manual   bool   // Inserted by hand rather than captured live
+	// CanDeadHeat is set when the capture has the same published time as the
+	// placed capture before it but has not been declared a dead heat
+	CanDeadHeat bool
+	Original    Duration // Time since the clock was started, as captured
+	Elapsed     Duration // Time since the corrected start
+	Time        Duration // Calibrated time, rounded by the session rules
+}
+
+// LaneResult is a lane's result for the results table
+type LaneResult struct {
+	Lane    int
+	Place   string      // Finishing place, place code, or empty if not captured
+	Timed   bool        // Whether Split and Time hold a captured finish
+	Split   Duration    // Raw capture time, rounded by the session rules
+	Time    Duration    // Calibrated time, rounded by the session rules
+	Capture int         // Index of the lane's capture, -1 if none
+	Splits  []LaneSplit // One per split station, nearest the start first
+}
+
+// Results is a snapshot of a race session's captures and lane results
+type Results struct {
+	State        State
+	Rules        Rules
+	Calibrated   bool
+	WinningTime  Duration
+	Correction   *StartCorrection // Nil if the start has not been corrected
+	StartSignals []Duration       // Start signals, since the clock was started
+	Captures     []CaptureResult
+	Lanes        []LaneResult         // One per lane, in lane order
+	Splits       []SplitStationResult // One per split station, nearest the start first
+}
+
+// Lane returns the result for a lane numbered from one
+func (r Results) Lane(lane int) LaneResult {
+	if lane < 1 || lane > len(r.Lanes) {
+		return LaneResult{Lane: lane, Capture: -1}
+	}
+	return r.Lanes[lane-1]
+}
+
+// Results returns a snapshot of the session. Captures are placed in finish
+// order, skipping unassigned captures and those whose lane has a place
+// code; lanes with a place code show the code instead of a place and time.
+// Captures in a dead heat share a place and the next place is skipped. With
+// a winning time set, every time is shifted so the first capture matches
+// it. Published times are rounded by the session rules; the raw elapsed
+// times are not.
+func (s *RaceSession) Results() Results {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := Results{
+		State:       s.state,
+		Rules:       s.rules,
+		Calibrated:  s.calibrated,
+		WinningTime: s.winningTime,
+		Captures:    make([]CaptureResult, len(s.captures)),
+		Lanes:       make([]LaneResult, s.laneCount),
+	}
+	for lane := 1; lane <= s.laneCount; lane++ {
+		results.Lanes[lane-1] = LaneResult{
+			Lane:    lane,
+			Place:   string(s.placeCodes[lane]),
+			Capture: -1,
+		}
+	}
+
+	if s.correction != nil {
+		correction := *s.correction
+		results.Correction = &correction
+	}
+	results.StartSignals = append([]Duration(nil), s.startSignals...)
+
+	offset := s.startOffset()
+
+	// Number the placed captures, giving each dead heat its first place
+	places := make([]int, len(s.captures))
+	shared := make(map[int]int)
+	position, previous := 0, -1
+	for i, c := range s.captures {
+		result := CaptureResult{
+			Lane:     c.lane,
+			Manual:   c.manual,
+			Original: c.elapsed,
+			Elapsed:  c.elapsed + offset,
+			Time:     s.publishedTime(i),
+		}
+		if !s.placed(i) {
+			result.Place = string(s.placeCodes[c.lane])
+			results.Captures[i] = result
+			continue
+		}
+
+		position++
+		places[i] = position
+		if previous >= 0 {
+			sameTime := results.Captures[previous].Time == result.Time
+			if c.deadHeat {
+				places[i] = places[previous]
+				result.DeadHeat = true
+			} else if sameTime {
+				result.CanDeadHeat = true
+			}
+		}
+		shared[places[i]]++
+		results.Captures[i] = result
+		previous = i
+	}
+
+	for i, c := range s.captures {
+		result := &results.Captures[i]
+		if places[i] > 0 {
+			result.Place = FormatPlace(places[i], shared[places[i]] > 1)
+		}
+
+		if c.lane == 0 {
+			continue
+		}
+		laneResult := &results.Lanes[c.lane-1]
+		laneResult.Capture = i
+		if s.placeCodes[c.lane] == PlaceNone {
+			laneResult.Place = result.Place
+			laneResult.Timed = true
+			laneResult.Split = s.rules.Round(result.Elapsed)
+			laneResult.Time = result.Time
+		}
+	}
+
+	s.splitResults(&results)
+	return results
+}
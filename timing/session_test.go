@@ -0,0 +1,236 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+// finish is a capture in a scripted race: its time after the start and the
+// lane it is assigned to, zero to leave it unassigned
+type finish struct {
+	at   time.Duration
+	lane int
+}
+
+// stoppedRace times a race on a manual clock, capturing each finish, then
+// stops it and assigns the lanes
+func stoppedRace(t *testing.T, laneCount int, finishes []finish) *RaceSession {
+	t.Helper()
+	clock := NewManualClock(raceStart)
+	session := NewRaceSessionWithClock(laneCount, clock)
+	if err := session.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	for _, f := range finishes {
+		clock.Set(raceStart.Add(f.at))
+		if _, err := session.Capture(); err != nil {
+			t.Fatalf("Capture() error = %v", err)
+		}
+	}
+	if err := session.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for i, f := range finishes {
+		if f.lane == 0 {
+			continue
+		}
+		if err := session.AssignLane(i, f.lane); err != nil {
+			t.Fatalf("AssignLane(%d, %d) error = %v", i, f.lane, err)
+		}
+	}
+	return session
+}
+
+// lanePlaces returns the place shown for each lane, lane 1 first
+func lanePlaces(results Results) []string {
+	places := make([]string, len(results.Lanes))
+	for i, lane := range results.Lanes {
+		places[i] = lane.Place
+	}
+	return places
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestResultsPlacing(t *testing.T) {
+	tests := []struct {
+		name     string
+		finishes []finish
+		codes    map[int]PlaceCode
+		want     []string // Place of each lane, lane 1 first
+	}{
+		{
+			name:     "finish order",
+			finishes: []finish{{400 * time.Second, 3}, {401 * time.Second, 1}, {402 * time.Second, 2}},
+			want:     []string{"2", "3", "1", ""},
+		},
+		{
+			name:     "unassigned captures take no place",
+			finishes: []finish{{390 * time.Second, 0}, {400 * time.Second, 2}, {401 * time.Second, 0}, {402 * time.Second, 1}},
+			want:     []string{"2", "1", "", ""},
+		},
+		{
+			name:     "DQ lane is renumbered around",
+			finishes: []finish{{400 * time.Second, 1}, {401 * time.Second, 2}, {402 * time.Second, 3}},
+			codes:    map[int]PlaceCode{1: PlaceDQ},
+			want:     []string{"DQ", "1", "2", ""},
+		},
+		{
+			name:     "DNF and DNS lanes",
+			finishes: []finish{{400 * time.Second, 2}, {401 * time.Second, 3}, {402 * time.Second, 4}},
+			codes:    map[int]PlaceCode{3: PlaceDNF, 1: PlaceDNS},
+			want:     []string{"DNS", "1", "DNF", "2"},
+		},
+		{
+			name:     "code cleared puts the lane back in finish order",
+			finishes: []finish{{400 * time.Second, 1}, {401 * time.Second, 2}},
+			codes:    map[int]PlaceCode{1: PlaceNone},
+			want:     []string{"1", "2", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 4, tt.finishes)
+			for lane, code := range tt.codes {
+				if err := session.SetPlaceCode(lane, code); err != nil {
+					t.Fatalf("SetPlaceCode(%d, %q) error = %v", lane, code, err)
+				}
+			}
+			if got := lanePlaces(session.Results()); !equalStrings(got, tt.want) {
+				t.Errorf("lane places = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultsDeadHeat(t *testing.T) {
+	tests := []struct {
+		name      string
+		finishes  []finish
+		deadHeats []int // Captures declared a dead heat with the one before
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "two crews share second",
+			finishes:  []finish{{400 * time.Second, 1}, {401*time.Second + 10*time.Millisecond, 2}, {401*time.Second + 50*time.Millisecond, 3}, {403 * time.Second, 4}},
+			deadHeats: []int{2},
+			want:      []string{"1", "2=", "2=", "4"},
+		},
+		{
+			name:      "three way tie for first",
+			finishes:  []finish{{400 * time.Second, 1}, {400 * time.Second, 2}, {400 * time.Second, 3}, {402 * time.Second, 4}},
+			deadHeats: []int{1, 2},
+			want:      []string{"1=", "1=", "1=", "4"},
+		},
+		{
+			name:      "different published times cannot dead heat",
+			finishes:  []finish{{400 * time.Second, 1}, {400*time.Second + 200*time.Millisecond, 2}},
+			deadHeats: []int{1},
+			wantErr:   true,
+		},
+		{
+			name:      "first capture has nothing to tie with",
+			finishes:  []finish{{400 * time.Second, 1}},
+			deadHeats: []int{0},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 4, tt.finishes)
+			var err error
+			for _, index := range tt.deadHeats {
+				if err = session.SetDeadHeat(index, true); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetDeadHeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := lanePlaces(session.Results()); !equalStrings(got, tt.want) {
+				t.Errorf("lane places = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultsCalibration(t *testing.T) {
+	tests := []struct {
+		name        string
+		finishes    []finish
+		codes       map[int]PlaceCode
+		winningTime Duration
+		want        []Duration // Published time of each lane, lane 1 first
+	}{
+		{
+			name:        "uncalibrated times are from the start",
+			finishes:    []finish{{400 * time.Second, 1}, {403*time.Second + 250*time.Millisecond, 2}},
+			winningTime: -1,
+			want:        []Duration{Duration(400 * time.Second), Duration(403*time.Second + 300*time.Millisecond)},
+		},
+		{
+			name:        "winning time shifts every lane",
+			finishes:    []finish{{400 * time.Second, 1}, {403*time.Second + 250*time.Millisecond, 2}},
+			winningTime: Duration(405 * time.Second),
+			want:        []Duration{Duration(405 * time.Second), Duration(408*time.Second + 300*time.Millisecond)},
+		},
+		{
+			name:        "winning time earlier than the clock",
+			finishes:    []finish{{410 * time.Second, 2}, {412 * time.Second, 1}},
+			winningTime: Duration(400 * time.Second),
+			want:        []Duration{Duration(402 * time.Second), Duration(400 * time.Second)},
+		},
+		{
+			name:        "false first capture is not the winner",
+			finishes:    []finish{{395 * time.Second, 0}, {400 * time.Second, 1}, {403*time.Second + 250*time.Millisecond, 2}},
+			winningTime: Duration(405 * time.Second),
+			want:        []Duration{Duration(405 * time.Second), Duration(408*time.Second + 300*time.Millisecond)},
+		},
+		{
+			name:        "DQ first finisher is not the winner",
+			finishes:    []finish{{398 * time.Second, 1}, {400 * time.Second, 2}},
+			codes:       map[int]PlaceCode{1: PlaceDQ},
+			winningTime: Duration(405 * time.Second),
+			want:        []Duration{0, Duration(405 * time.Second)}, // A DQ lane has no time
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 2, tt.finishes)
+			for lane, code := range tt.codes {
+				if err := session.SetPlaceCode(lane, code); err != nil {
+					t.Fatalf("SetPlaceCode(%d, %q) error = %v", lane, code, err)
+				}
+			}
+			if tt.winningTime >= 0 {
+				session.SetWinningTime(tt.winningTime)
+			}
+			results := session.Results()
+			if results.Calibrated != (tt.winningTime >= 0) {
+				t.Errorf("Calibrated = %v, want %v", results.Calibrated, tt.winningTime >= 0)
+			}
+			for i, want := range tt.want {
+				if got := results.Lanes[i].Time; got != want {
+					t.Errorf("lane %d time = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package timing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitCapture is a crossing captured at an intermediate split station
+type splitCapture struct {
+	elapsed Duration // Time since the clock was started, as captured
+	lane    int      // Zero until assigned
+}
+
+// ParseSplitDistances parses a list of split distances in metres such as
+// "500, 1000, 1500". An empty list has no split stations.
+func ParseSplitDistances(text string) ([]int, error) {
+	distances := make([]int, 0)
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		distance, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(field), "m"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid split distance %q", field)
+		}
+		distances = append(distances, distance)
+	}
+	if err := checkSplitDistances(distances); err != nil {
+		return nil, err
+	}
+	return distances, nil
+}
+
+// FormatSplitDistances formats split distances the way ParseSplitDistances reads them
+func FormatSplitDistances(distances []int) string {
+	fields := make([]string, len(distances))
+	for i, distance := range distances {
+		fields[i] = strconv.Itoa(distance)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// checkSplitDistances requires positive distances in increasing order
+func checkSplitDistances(distances []int) error {
+	for i, distance := range distances {
+		if distance < 1 {
+			return fmt.Errorf("split distance must be 1 m or more, got %d", distance)
+		}
+		if i > 0 && distance <= distances[i-1] {
+			return fmt.Errorf("split distances must increase, got %d m after %d m", distance, distances[i-1])
+		}
+	}
+	return nil
+}
+
+// SetSplitStations sets the distances in metres of the split stations along
+// the course. The session must be cleared.
+func (s *RaceSession) SetSplitStations(distances []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateCleared {
+		return fmt.Errorf("split stations can only be changed before the race starts")
+	}
+	if err := checkSplitDistances(distances); err != nil {
+		return err
+	}
+	s.splitDistances = append([]int(nil), distances...)
+	s.splits = make([][]splitCapture, len(distances))
+	s.record(Event{Kind: EventSplitStations, Distances: s.splitDistances})
+	return nil
+}
+
+// SplitStations returns the distances of the split stations, nearest the start first
+func (s *RaceSession) SplitStations() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.splitDistances...)
+}
+
+// CaptureSplit records a crossing at a split station and returns its index.
+// It stays unassigned until its lane is entered.
+func (s *RaceSession) CaptureSplit(station int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0, fmt.Errorf("race is not running")
+	}
+	if err := s.checkStation(station); err != nil {
+		return 0, err
+	}
+	elapsed := Duration(s.clock.Now().Sub(s.startTime))
+	s.splits[station] = append(s.splits[station], splitCapture{elapsed: elapsed})
+	s.record(Event{Kind: EventCaptureSplit, Station: station, Time: elapsed})
+	return len(s.splits[station]) - 1, nil
+}
+
+// AssignSplit assigns a split capture to a lane, or unassigns it when lane is
+// zero. Each lane can only be assigned once at each station.
+func (s *RaceSession) AssignSplit(station, index, lane int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkStation(station); err != nil {
+		return err
+	}
+	captures := s.splits[station]
+	if index < 0 || index >= len(captures) {
+		return fmt.Errorf("no capture %d at the %d m split", index+1, s.splitDistances[station])
+	}
+	if lane != 0 {
+		if err := s.checkLane(lane); err != nil {
+			return err
+		}
+		for i, c := range captures {
+			if i != index && c.lane == lane {
+				return fmt.Errorf("lane %d is already assigned to capture %d at the %d m split", lane, i+1, s.splitDistances[station])
+			}
+		}
+	}
+	captures[index].lane = lane
+	s.record(Event{Kind: EventAssignSplit, Station: station, Index: index, Lane: lane})
+	return nil
+}
+
+// DeleteSplit removes a split capture, such as one taken by mistake
+func (s *RaceSession) DeleteSplit(station, index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkStation(station); err != nil {
+		return err
+	}
+	captures := s.splits[station]
+	if index < 0 || index >= len(captures) {
+		return fmt.Errorf("no capture %d at the %d m split", index+1, s.splitDistances[station])
+	}
+	s.splits[station] = append(captures[:index], captures[index+1:]...)
+	s.record(Event{Kind: EventDeleteSplit, Station: station, Index: index})
+	return nil
+}
+
+func (s *RaceSession) checkStation(station int) error {
+	if station < 0 || station >= len(s.splitDistances) {
+		return fmt.Errorf("no split station %d", station+1)
+	}
+	return nil
+}
+
+// SplitCaptureResult is a split capture as shown in a station's capture list
+type SplitCaptureResult struct {
+	Lane int      // Zero if unassigned
+	Time Duration // Calibrated time, rounded by the session rules
+}
+
+// SplitStationResult is a split station's captures
+type SplitStationResult struct {
+	Distance int // Metres from the start
+	Captures []SplitCaptureResult
+}
+
+// LaneSplit is a lane's time at a split station
+type LaneSplit struct {
+	Distance int
+	Timed    bool     // Whether the lane was captured at this station
+	Time     Duration // Calibrated time from the start, rounded by the session rules
+	// Segment is the time since the lane's previous split, or since the start
+	// at the first station. It is only set when both ends were captured.
+	Segment    Duration
+	HasSegment bool
+}
+
+// splitResults fills in the split stations and each lane's splits
+func (s *RaceSession) splitResults(results *Results) {
+	shift := s.startOffset() + s.calibration()
+	results.Splits = make([]SplitStationResult, len(s.splitDistances))
+	for i := range results.Lanes {
+		results.Lanes[i].Splits = make([]LaneSplit, len(s.splitDistances))
+	}
+
+	for station, distance := range s.splitDistances {
+		stationResult := SplitStationResult{
+			Distance: distance,
+			Captures: make([]SplitCaptureResult, len(s.splits[station])),
+		}
+		for i, c := range s.splits[station] {
+			stationResult.Captures[i] = SplitCaptureResult{Lane: c.lane, Time: s.rules.Round(c.elapsed + shift)}
+		}
+		results.Splits[station] = stationResult
+
+		for lane := range results.Lanes {
+			results.Lanes[lane].Splits[station].Distance = distance
+		}
+		for _, c := range stationResult.Captures {
+			if c.Lane == 0 {
+				continue
+			}
+			split := &results.Lanes[c.Lane-1].Splits[station]
+			split.Timed = true
+			split.Time = c.Time
+		}
+	}
+
+	// Segments run from each split to the next, the first from the start
+	for i := range results.Lanes {
+		splits := results.Lanes[i].Splits
+		for station := range splits {
+			if !splits[station].Timed {
+				continue
+			}
+			switch {
+			case station == 0:
+				splits[station].Segment = splits[station].Time
+				splits[station].HasSegment = true
+			case splits[station-1].Timed:
+				splits[station].Segment = splits[station].Time - splits[station-1].Time
+				splits[station].HasSegment = true
+			}
+		}
+	}
+}
+
+// FinishSegment returns the time from the lane's last split station to the
+// finish, if both were captured
+func (r LaneResult) FinishSegment() (Duration, bool) {
+	if !r.Timed || len(r.Splits) == 0 || !r.Splits[len(r.Splits)-1].Timed {
+		return 0, false
+	}
+	return r.Time - r.Splits[len(r.Splits)-1].Time, true
+}
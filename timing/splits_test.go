@@ -0,0 +1,173 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSplitDistances(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []int
+		wantErr bool
+	}{
+		{text: "500, 1000, 1500", want: []int{500, 1000, 1500}},
+		{text: "1000m", want: []int{1000}},
+		{text: "500 1000", want: []int{500, 1000}},
+		{text: "", want: []int{}},
+		{text: "1000, 500", wantErr: true},
+		{text: "500, 500", wantErr: true},
+		{text: "0", wantErr: true},
+		{text: "half", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseSplitDistances(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSplitDistances(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalInts(got, tt.want) {
+				t.Errorf("ParseSplitDistances(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// splitCrossing is a crossing at a split station in a scripted race
+type splitCrossing struct {
+	station int
+	at      time.Duration
+	lane    int // Zero to leave the capture unassigned
+}
+
+func TestLaneSplits(t *testing.T) {
+	tests := []struct {
+		name      string
+		crossings []splitCrossing
+		want      []LaneSplit // Lane 1's splits, one per station
+		wantEnd   Duration    // Lane 1's finish segment, -1 if it has none
+	}{
+		{
+			name: "every station",
+			crossings: []splitCrossing{
+				{0, 100 * time.Second, 1},
+				{1, 205 * time.Second, 1},
+			},
+			want: []LaneSplit{
+				{Distance: 500, Timed: true, Time: Duration(100 * time.Second), Segment: Duration(100 * time.Second), HasSegment: true},
+				{Distance: 1000, Timed: true, Time: Duration(205 * time.Second), Segment: Duration(105 * time.Second), HasSegment: true},
+			},
+			wantEnd: Duration(195 * time.Second),
+		},
+		{
+			name: "missed first station",
+			crossings: []splitCrossing{
+				{1, 205 * time.Second, 1},
+			},
+			want: []LaneSplit{
+				{Distance: 500},
+				{Distance: 1000, Timed: true, Time: Duration(205 * time.Second)},
+			},
+			wantEnd: Duration(195 * time.Second),
+		},
+		{
+			name: "unassigned and other lanes' captures",
+			crossings: []splitCrossing{
+				{0, 99 * time.Second, 0},
+				{0, 100 * time.Second, 2},
+				{0, 101 * time.Second, 1},
+			},
+			want: []LaneSplit{
+				{Distance: 500, Timed: true, Time: Duration(101 * time.Second), Segment: Duration(101 * time.Second), HasSegment: true},
+				{Distance: 1000},
+			},
+			wantEnd: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(raceStart)
+			session := NewRaceSessionWithClock(2, clock)
+			must(t, session.SetSplitStations([]int{500, 1000}))
+			must(t, session.Start())
+			indexes := make([]int, len(tt.crossings))
+			for i, c := range tt.crossings {
+				clock.Set(raceStart.Add(c.at))
+				index, err := session.CaptureSplit(c.station)
+				must(t, err)
+				indexes[i] = index
+			}
+			clock.Set(raceStart.Add(400 * time.Second))
+			_, err := session.Capture()
+			must(t, err)
+			must(t, session.Stop())
+			must(t, session.AssignLane(0, 1))
+			for i, c := range tt.crossings {
+				if c.lane != 0 {
+					must(t, session.AssignSplit(c.station, indexes[i], c.lane))
+				}
+			}
+
+			result := session.Results().Lane(1)
+			if len(result.Splits) != len(tt.want) {
+				t.Fatalf("%d splits, want %d", len(result.Splits), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := result.Splits[i]; got != want {
+					t.Errorf("split %d = %+v, want %+v", i+1, got, want)
+				}
+			}
+			end, ok := result.FinishSegment()
+			if ok != (tt.wantEnd >= 0) || (ok && end != tt.wantEnd) {
+				t.Errorf("FinishSegment() = %v, %v, want %v", end, ok, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestSplitCaptureErrors(t *testing.T) {
+	clock := NewManualClock(raceStart)
+	session := NewRaceSessionWithClock(2, clock)
+	must(t, session.SetSplitStations([]int{1000}))
+	if _, err := session.CaptureSplit(0); err == nil {
+		t.Error("CaptureSplit() before the start: error = nil, want an error")
+	}
+	must(t, session.Start())
+	if err := session.SetSplitStations([]int{500}); err == nil {
+		t.Error("SetSplitStations() after the start: error = nil, want an error")
+	}
+	if _, err := session.CaptureSplit(1); err == nil {
+		t.Error("CaptureSplit() at a missing station: error = nil, want an error")
+	}
+	clock.Advance(200 * time.Second)
+	_, err := session.CaptureSplit(0)
+	must(t, err)
+	_, err = session.CaptureSplit(0)
+	must(t, err)
+
+	// Split lanes can be entered while the race is running
+	must(t, session.AssignSplit(0, 0, 1))
+	tests := []struct {
+		name                 string
+		station, index, lane int
+	}{
+		{name: "lane already assigned", station: 0, index: 1, lane: 1},
+		{name: "lane off the course", station: 0, index: 1, lane: 3},
+		{name: "no such capture", station: 0, index: 2, lane: 2},
+		{name: "no such station", station: 1, index: 0, lane: 2},
+	}
+	for _, tt := range tests {
+		if err := session.AssignSplit(tt.station, tt.index, tt.lane); err == nil {
+			t.Errorf("%s: AssignSplit(%d, %d, %d) error = nil, want an error", tt.name, tt.station, tt.index, tt.lane)
+		}
+	}
+
+	// Unassigning frees the lane for another capture
+	must(t, session.AssignSplit(0, 0, 0))
+	must(t, session.AssignSplit(0, 1, 1))
+	if got := session.Results().Splits[0].Captures; got[0].Lane != 0 || got[1].Lane != 1 {
+		t.Errorf("split captures = %+v, want capture 2 in lane 1", got)
+	}
+}
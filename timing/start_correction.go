@@ -0,0 +1,108 @@
+package timing
+
+import (
+	"fmt"
+)
+
+// StartCorrection moves the start of a race after the fact. Captures keep
+// the times they were taken at; the offset is added to each of them, so a
+// start pressed two seconds late is corrected with an offset of +2s.
+type StartCorrection struct {
+	Offset Duration
+	Signal int // Index of the start signal used, -1 for an entered offset
+}
+
+// Source describes where the correction came from
+func (c StartCorrection) Source() string {
+	if c.Signal >= 0 {
+		return fmt.Sprintf("start signal %d", c.Signal+1)
+	}
+	return "entered offset"
+}
+
+// CaptureStartSignal records the moment the real start was signalled, for
+// example a start heard over the radio after the clock was started early,
+// and returns its index. It does not change any times until it is chosen
+// with UseStartSignal.
+func (s *RaceSession) CaptureStartSignal() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != StateRunning {
+		return 0, fmt.Errorf("race is not running")
+	}
+	elapsed := Duration(s.clock.Now().Sub(s.startTime))
+	s.startSignals = append(s.startSignals, elapsed)
+	s.record(Event{Kind: EventStartSignal, Time: elapsed})
+	return len(s.startSignals) - 1, nil
+}
+
+// SetStartOffset corrects the start by a known offset, positive when the
+// clock was started late and negative when it was started early
+func (s *RaceSession) SetStartOffset(offset Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("the start cannot be corrected while the race is running")
+	}
+	s.correction = &StartCorrection{Offset: offset, Signal: -1}
+	s.record(Event{Kind: EventStartOffset, Time: offset})
+	return nil
+}
+
+// UseStartSignal rebases every capture on a captured start signal, so the
+// signal becomes the start of the race
+func (s *RaceSession) UseStartSignal(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("the start cannot be corrected while the race is running")
+	}
+	if index < 0 || index >= len(s.startSignals) {
+		return fmt.Errorf("no start signal %d", index+1)
+	}
+	s.correction = &StartCorrection{Offset: -s.startSignals[index], Signal: index}
+	s.record(Event{Kind: EventUseStartSignal, Index: index})
+	return nil
+}
+
+// ClearStartCorrection goes back to timing from when the clock was started
+func (s *RaceSession) ClearStartCorrection() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.correction = nil
+	s.record(Event{Kind: EventClearStartCorrection})
+}
+
+// startOffset returns the offset added to captured times
+func (s *RaceSession) startOffset() Duration {
+	if s.correction == nil {
+		return 0
+	}
+	return s.correction.Offset
+}
+
+// ParseOffset parses a start offset such as "+1.5", "-0.8" or "-00:02.0".
+// Offsets without a sign are positive.
+func ParseOffset(value string) (Duration, error) {
+	sign := Duration(1)
+	switch {
+	case len(value) > 0 && value[0] == '-':
+		sign = -1
+		value = value[1:]
+	case len(value) > 0 && value[0] == '+':
+		value = value[1:]
+	}
+	d, err := ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return sign * d, nil
+}
+
+// FormatOffset formats an offset with its sign, such as "+00:01.5"
+func FormatOffset(d Duration, p Precision) string {
+	if d < 0 {
+		return d.Format(p)
+	}
+	return "+" + d.Format(p)
+}
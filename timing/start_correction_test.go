@@ -0,0 +1,153 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetStartOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   []Duration // Published time of each lane, lane 1 first
+	}{
+		{
+			name:   "clock started late",
+			offset: 2 * time.Second,
+			want:   []Duration{Duration(402 * time.Second), Duration(405 * time.Second)},
+		},
+		{
+			name:   "clock started early",
+			offset: -1500 * time.Millisecond,
+			want:   []Duration{Duration(398*time.Second + 500*time.Millisecond), Duration(401*time.Second + 500*time.Millisecond)},
+		},
+		{
+			name: "no offset",
+			want: []Duration{Duration(400 * time.Second), Duration(403 * time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := stoppedRace(t, 2, []finish{{400 * time.Second, 1}, {403 * time.Second, 2}})
+			if err := session.SetStartOffset(Duration(tt.offset)); err != nil {
+				t.Fatalf("SetStartOffset() error = %v", err)
+			}
+			results := session.Results()
+			if results.Correction == nil || results.Correction.Signal != -1 || results.Correction.Source() != "entered offset" {
+				t.Errorf("Correction = %+v, want an entered offset", results.Correction)
+			}
+			for i, want := range tt.want {
+				if got := results.Lanes[i].Time; got != want {
+					t.Errorf("lane %d time = %v, want %v", i+1, got, want)
+				}
+			}
+			// Captures keep the time they were taken at
+			if got := results.Captures[0].Original; got != Duration(400*time.Second) {
+				t.Errorf("capture 1 original = %v, want 06:40.0", got)
+			}
+		})
+	}
+}
+
+func TestUseStartSignal(t *testing.T) {
+	clock := NewManualClock(raceStart)
+	session := NewRaceSessionWithClock(2, clock)
+	must(t, session.Start())
+	if err := session.SetStartOffset(Duration(time.Second)); err == nil {
+		t.Error("SetStartOffset() while running: error = nil, want an error")
+	}
+	// A false start signal, then the real one
+	clock.Advance(time.Second)
+	_, err := session.CaptureStartSignal()
+	must(t, err)
+	clock.Advance(2 * time.Second)
+	_, err = session.CaptureStartSignal()
+	must(t, err)
+	clock.Advance(400 * time.Second)
+	_, err = session.Capture()
+	must(t, err)
+	must(t, session.Stop())
+	must(t, session.AssignLane(0, 1))
+
+	tests := []struct {
+		name    string
+		signal  int
+		want    Duration // Lane 1's published time
+		wantErr bool
+	}{
+		{name: "first signal", signal: 0, want: Duration(402 * time.Second)},
+		{name: "second signal", signal: 1, want: Duration(400 * time.Second)},
+		{name: "no such signal", signal: 2, wantErr: true},
+		{name: "negative index", signal: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := session.UseStartSignal(tt.signal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UseStartSignal(%d) error = %v, wantErr %v", tt.signal, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			results := session.Results()
+			if got := results.Lane(1).Time; got != tt.want {
+				t.Errorf("lane 1 time = %v, want %v", got, tt.want)
+			}
+			if results.Correction == nil || results.Correction.Signal != tt.signal {
+				t.Errorf("Correction = %+v, want start signal %d", results.Correction, tt.signal+1)
+			}
+		})
+	}
+
+	session.ClearStartCorrection()
+	if results := session.Results(); results.Correction != nil || results.Lane(1).Time != Duration(403*time.Second) {
+		t.Errorf("after ClearStartCorrection, correction = %+v and lane 1 time = %v", results.Correction, results.Lane(1).Time)
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "+1.5", want: 1500 * time.Millisecond},
+		{value: "1.5", want: 1500 * time.Millisecond},
+		{value: "-0.8", want: -800 * time.Millisecond},
+		{value: "-00:02.0", want: -2 * time.Second},
+		{value: "", wantErr: true},
+		{value: "+-1.0", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseOffset(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOffset(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != Duration(tt.want) {
+				t.Errorf("ParseOffset(%q) = %v, want %v", tt.value, got, Duration(tt.want))
+			}
+		})
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 1500 * time.Millisecond, want: "+00:01.5"},
+		{d: 0, want: "+00:00.0"},
+		{d: -800 * time.Millisecond, want: "-00:00.8"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatOffset(Duration(tt.d), Tenths); got != tt.want {
+			t.Errorf("FormatOffset(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package timing
+
+import (
+	"fmt"
+	"time"
+)
+
+// undoable reports whether an event is a result edit that can be undone.
+// Anything else, such as a capture or the race being stopped, ends the
+// undo history.
+func undoable(kind EventKind) bool {
+	switch kind {
+	case EventAssignLane, EventAssignSplit, EventDeleteSplit, EventPlaceCode,
+		EventCaptureTime, EventDeadHeat, EventDeleteCapture, EventInsertCapture,
+		EventMoveCapture, EventWinningTime, EventClearWinningTime,
+		EventStartOffset, EventUseStartSignal, EventClearStartCorrection:
+		return true
+	}
+	return false
+}
+
+// coalesces reports whether next replaces previous in the undo history
+// rather than adding to it, so typing a time a character at a time is
+// undone in one step
+func coalesces(previous, next Event) bool {
+	winningTime := func(kind EventKind) bool {
+		return kind == EventWinningTime || kind == EventClearWinningTime
+	}
+	if winningTime(previous.Kind) && winningTime(next.Kind) {
+		// Partly typed winning times clear the calibration
+		return true
+	}
+	if previous.Kind != next.Kind {
+		return false
+	}
+	switch next.Kind {
+	case EventStartOffset:
+		return true
+	case EventCaptureTime, EventAssignLane:
+		return previous.Index == next.Index
+	case EventAssignSplit:
+		return previous.Station == next.Station && previous.Index == next.Index
+	}
+	return false
+}
+
+// keep adds a change to the events in effect. A new edit clears the redo
+// history. The session lock must be held.
+func (s *RaceSession) keep(event Event) {
+	s.redo = nil
+	if last := len(s.applied) - 1; last >= 0 && coalesces(s.applied[last], event) {
+		s.applied[last] = event
+		return
+	}
+	s.applied = append(s.applied, event)
+}
+
+// Undo reverts the most recent result edit since the race was stopped
+func (s *RaceSession) Undo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("edits cannot be undone while the race is running")
+	}
+	last := len(s.applied) - 1
+	if last < 0 || !undoable(s.applied[last].Kind) {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	undone := s.applied[last]
+	if err := s.rebuild(s.applied[:last]); err != nil {
+		return err
+	}
+	s.applied = s.applied[:last]
+	s.redo = append(s.redo, undone)
+	s.record(Event{Kind: EventUndo})
+	return nil
+}
+
+// Redo makes the most recently undone edit again
+func (s *RaceSession) Redo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateRunning {
+		return fmt.Errorf("edits cannot be redone while the race is running")
+	}
+	if len(s.redo) == 0 {
+		return fmt.Errorf("nothing to redo")
+	}
+
+	redone := s.redo[len(s.redo)-1]
+	applied := append(append([]Event(nil), s.applied...), redone)
+	if err := s.rebuild(applied); err != nil {
+		return err
+	}
+	s.applied = applied
+	s.redo = s.redo[:len(s.redo)-1]
+	s.record(Event{Kind: EventRedo})
+	return nil
+}
+
+// CanUndo reports whether there is an edit to undo
+func (s *RaceSession) CanUndo() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last := len(s.applied) - 1
+	return s.state != StateRunning && last >= 0 && undoable(s.applied[last].Kind)
+}
+
+// CanRedo reports whether there is an undone edit to redo
+func (s *RaceSession) CanRedo() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state != StateRunning && len(s.redo) > 0
+}
+
+// rebuild replaces the session's state with the result of making events
+// from a cleared session. The session lock must be held.
+func (s *RaceSession) rebuild(events []Event) error {
+	clock := NewManualClock(time.Time{})
+	fresh := NewRaceSessionWithClock(s.laneCount, clock)
+	for _, event := range events {
+		if err := fresh.apply(clock, event); err != nil {
+			return fmt.Errorf("failed to rebuild the race from its %s event: %v", event.Kind, err)
+		}
+	}
+
+	s.rules = fresh.rules
+	s.state = fresh.state
+	s.startTime = fresh.startTime
+	s.captures = fresh.captures
+	s.placeCodes = fresh.placeCodes
+	s.winningTime = fresh.winningTime
+	s.calibrated = fresh.calibrated
+	s.startSignals = fresh.startSignals
+	s.correction = fresh.correction
+	s.splitDistances = fresh.splitDistances
+	s.splits = fresh.splits
+	return nil
+}
+
+// HistoryEntry is a result edit in a session's undo history
+type HistoryEntry struct {
+	Description string
+	Undone      bool // Whether the edit has been undone and can be redone
+}
+
+// History returns the result edits since the race was stopped, oldest
+// first, followed by any undone edits in the order they would be redone
+func (s *RaceSession) History() []HistoryEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first := len(s.applied)
+	for first > 0 && undoable(s.applied[first-1].Kind) {
+		first--
+	}
+	history := make([]HistoryEntry, 0, len(s.applied)-first+len(s.redo))
+	for _, event := range s.applied[first:] {
+		history = append(history, HistoryEntry{Description: s.describe(event)})
+	}
+	for i := len(s.redo) - 1; i >= 0; i-- {
+		history = append(history, HistoryEntry{Description: s.describe(s.redo[i]), Undone: true})
+	}
+	return history
+}
+
+// describe says what a result edit did. The session lock must be held.
+func (s *RaceSession) describe(event Event) string {
+	precision := s.rules.Precision
+	split := func() string {
+		if event.Station >= 0 && event.Station < len(s.splitDistances) {
+			return fmt.Sprintf("%dm split %d", s.splitDistances[event.Station], event.Index+1)
+		}
+		return fmt.Sprintf("Split %d", event.Index+1)
+	}
+
+	switch event.Kind {
+	case EventAssignLane:
+		if event.Lane == 0 {
+			return fmt.Sprintf("Capture %d unassigned", event.Index+1)
+		}
+		return fmt.Sprintf("Capture %d to lane %d", event.Index+1, event.Lane)
+	case EventAssignSplit:
+		if event.Lane == 0 {
+			return fmt.Sprintf("%s unassigned", split())
+		}
+		return fmt.Sprintf("%s to lane %d", split(), event.Lane)
+	case EventDeleteSplit:
+		return fmt.Sprintf("Deleted %s", split())
+	case EventPlaceCode:
+		if event.Code == PlaceNone {
+			return fmt.Sprintf("Lane %d back in finish order", event.Lane)
+		}
+		return fmt.Sprintf("Lane %d %s", event.Lane, event.Code)
+	case EventCaptureTime:
+		return fmt.Sprintf("Capture %d time set to %s", event.Index+1, precision.Format(event.Time))
+	case EventDeadHeat:
+		if event.DeadHeat {
+			return fmt.Sprintf("Capture %d dead heat", event.Index+1)
+		}
+		return fmt.Sprintf("Capture %d dead heat removed", event.Index+1)
+	case EventDeleteCapture:
+		return fmt.Sprintf("Deleted capture %d", event.Index+1)
+	case EventInsertCapture:
+		return fmt.Sprintf("Inserted capture at %s", precision.Format(event.Time))
+	case EventMoveCapture:
+		if event.Delta < 0 {
+			return fmt.Sprintf("Moved capture %d up", event.Index+1)
+		}
+		return fmt.Sprintf("Moved capture %d down", event.Index+1)
+	case EventWinningTime:
+		return fmt.Sprintf("Winning time %s", precision.Format(event.Time))
+	case EventClearWinningTime:
+		return "Winning time cleared"
+	case EventStartOffset:
+		return fmt.Sprintf("Start corrected by %s", FormatOffset(event.Time, precision))
+	case EventUseStartSignal:
+		return fmt.Sprintf("Start from signal %d", event.Index+1)
+	case EventClearStartCorrection:
+		return "Start correction removed"
+	}
+	return string(event.Kind)
+}
@@ -0,0 +1,70 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/comagnaw/regattaClock/timing"
+)
+
+// timingRules returns the loaded regatta's precision and rounding, or the
+// defaults before a regatta is loaded
+func (a *App) timingRules() timing.Rules {
+	if a.regattaData == nil {
+		return timing.DefaultRules()
+	}
+	return a.regattaData.Timing
+}
+
+// showTimingRules lets the user choose the regatta's timing precision and
+// how published times are rounded
+func (a *App) showTimingRules() {
+	if a.regattaData == nil {
+		dialog.ShowError(fmt.Errorf("load a regatta before setting its timing precision"), a.window)
+		return
+	}
+	rules := a.regattaData.Timing
+
+	precisions := make([]string, len(timing.Precisions))
+	for i, precision := range timing.Precisions {
+		precisions[i] = precision.String()
+	}
+	precisionSelect := widget.NewSelect(precisions, nil)
+	precisionSelect.SetSelected(rules.Precision.String())
+
+	roundings := make([]string, len(timing.RoundingPolicies))
+	for i, rounding := range timing.RoundingPolicies {
+		roundings[i] = rounding.String()
+	}
+	roundingSelect := widget.NewSelect(roundings, nil)
+	roundingSelect.SetSelected(rules.Rounding.String())
+
+	dialog.ShowForm(
+		"Timing Precision",
+		"Apply",
+		"Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Precision", precisionSelect),
+			widget.NewFormItem("Published times", roundingSelect),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			precision, err := timing.ParsePrecision(precisionSelect.Selected)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			rounding, err := timing.ParseRounding(roundingSelect.Selected)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			a.regattaData.Timing = timing.Rules{Precision: precision, Rounding: rounding}
+			dialog.ShowInformation("Timing Precision", "Race windows opened from now on will use the new precision", a.window)
+		},
+		a.window,
+	)
+}
@@ -0,0 +1,147 @@
+package regattaClock
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// setupUndoShortcuts binds Ctrl+Z and Ctrl+Y (Cmd on macOS) in a window to
+// undo and redo in the race. Entries being typed in keep these keys for
+// themselves.
+func (a *App) setupUndoShortcuts(canvas fyne.Canvas) {
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		a.undo()
+	})
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		a.redo()
+	})
+}
+
+// undo reverts the race's most recent result edit
+func (a *App) undo() {
+	if !a.session.CanUndo() {
+		return
+	}
+	if err := a.session.Undo(); err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.refreshUndone()
+}
+
+// redo makes the race's most recently undone edit again
+func (a *App) redo() {
+	if !a.session.CanRedo() {
+		return
+	}
+	if err := a.session.Redo(); err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.refreshUndone()
+}
+
+// refreshUndone shows the results after an undo or redo, including the
+// winning time entry, which the session may have changed underneath it
+func (a *App) refreshUndone() {
+	results := a.session.Results()
+	a.refreshing = true
+	if results.Calibrated {
+		a.winningTime.SetText(results.Rules.Precision.Format(results.WinningTime))
+	} else {
+		a.winningTime.SetText(emptyString)
+	}
+	a.refreshing = false
+
+	a.refreshContent()
+	a.updateRefereeButton()
+}
+
+// showHistory opens a window listing the race's result edits since it was
+// stopped, with buttons to undo and redo them
+func (a *App) showHistory() {
+	if a.historyWindow != nil {
+		a.historyWindow.RequestFocus()
+		return
+	}
+	historyWindow := a.app.NewWindow(fmt.Sprintf("History - %s", a.window.Title()))
+
+	a.history = a.session.History()
+	a.historyList = widget.NewList(
+		func() int {
+			return len(a.history)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel(emptyString)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			label := item.(*widget.Label)
+			entry := a.history[id]
+			label.TextStyle = fyne.TextStyle{Italic: entry.Undone}
+			if entry.Undone {
+				label.SetText(fmt.Sprintf("%d. %s (undone)", id+1, entry.Description))
+				return
+			}
+			label.SetText(fmt.Sprintf("%d. %s", id+1, entry.Description))
+		},
+	)
+
+	undoButton := widget.NewButton("Undo", a.undo)
+	redoButton := widget.NewButton("Redo", a.redo)
+	closeButton := widget.NewButton("Close", func() {
+		historyWindow.Close()
+	})
+	a.historyButtons = []*widget.Button{undoButton, redoButton}
+	a.refreshHistory()
+
+	historyWindow.SetContent(container.NewBorder(
+		nil,
+		container.NewHBox(
+			layout.NewSpacer(),
+			undoButton,
+			redoButton,
+			closeButton,
+			layout.NewSpacer(),
+		),
+		nil,
+		nil,
+		a.historyList,
+	))
+	a.setupUndoShortcuts(historyWindow.Canvas())
+	historyWindow.SetOnClosed(func() {
+		a.historyWindow = nil
+		a.historyList = nil
+		a.historyButtons = nil
+	})
+	historyWindow.Resize(fyne.NewSize(420, 360))
+	a.historyWindow = historyWindow
+	historyWindow.Show()
+}
+
+// refreshHistory updates the history window, if it is open, and enables its
+// buttons when there is something to undo or redo
+func (a *App) refreshHistory() {
+	if a.historyList == nil {
+		return
+	}
+	a.history = a.session.History()
+	a.historyList.Refresh()
+	if len(a.history) > 0 {
+		a.historyList.ScrollToBottom()
+	}
+
+	enable := []bool{a.session.CanUndo(), a.session.CanRedo()}
+	for i, button := range a.historyButtons {
+		if enable[i] {
+			button.Enable()
+		} else {
+			button.Disable()
+		}
+	}
+}